@@ -8,10 +8,100 @@ import (
 	"github.com/voidarchive/pokedex/internal/shared/constants"
 )
 
+// Combatant is a Pokemon's battle state. HP is tracked here so it can carry over
+// from one round to the next, e.g. while weakening a wild Pokemon before catching it.
+type Combatant struct {
+	Name    string
+	Color   string // ANSI color used for the name in battle messages
+	HP      int
+	MaxHP   int
+	Attack  int
+	Defense int
+	Speed   int
+	Types   []string
+	Status  Status // Inflicted by attacks in battle; see status.go
+}
+
+// NewCombatant builds a Combatant at full HP from a Pokemon's base stats.
+func NewCombatant(pokemon pokeapi.PokemonData, color string) Combatant {
+	hp, _ := pokemon.GetStat("hp")
+	attack, _ := pokemon.GetStat("attack")
+	defense, _ := pokemon.GetStat("defense")
+	speed, _ := pokemon.GetStat("speed")
+	var types []string
+	for _, t := range pokemon.Types {
+		types = append(types, t.Type.Name)
+	}
+	return Combatant{
+		Name:    pokemon.Name,
+		Color:   color,
+		HP:      hp,
+		MaxHP:   hp,
+		Attack:  attack,
+		Defense: defense,
+		Speed:   speed,
+		Types:   types,
+	}
+}
+
+// Fainted reports whether the combatant has no HP left.
+func (c Combatant) Fainted() bool {
+	return c.HP <= 0
+}
+
+// Round plays one turn: the faster combatant attacks first (the player wins speed ties),
+// then the other one strikes back if it is still standing. A status can keep either
+// from attacking, and burns and poison hurt at the end of the round.
+func Round(r *rand.Rand, player, opponent *Combatant) {
+	first, second := player, opponent
+	if opponent.Speed > player.Speed {
+		first, second = opponent, player
+	}
+	if takeTurn(r, first, second) || takeTurn(r, second, first) {
+		return
+	}
+	if !statusDamage(first) {
+		statusDamage(second)
+	}
+}
+
+// takeTurn lets attacker attack if its status allows, and reports whether the defender
+// fainted.
+func takeTurn(r *rand.Rand, attacker, defender *Combatant) bool {
+	if !canMove(r, attacker) {
+		return false
+	}
+	return attack(r, attacker, defender)
+}
+
+// attack makes attacker hit defender once, possibly inflicting a status, and reports
+// whether the defender fainted.
+func attack(r *rand.Rand, attacker, defender *Combatant) bool {
+	fmt.Printf("  %s%s%s attacks %s%s%s!\n", attacker.Color, attacker.Name, constants.ColorReset, defender.Color, defender.Name, constants.ColorReset)
+	damage := CalculateDamage(r, attacker.Attack, defender.Defense)
+	defender.HP -= damage
+	fmt.Printf("  %s%s%s takes %s%d%s damage.\n", defender.Color, defender.Name, constants.ColorReset, constants.ColorBrightRed, damage, constants.ColorReset)
+
+	if defender.Fainted() {
+		fmt.Printf("  %s%s%s fainted!%s\n", defender.Color, defender.Name, constants.ColorRed, constants.ColorReset)
+		return true
+	}
+	inflictStatus(r, attacker, defender)
+	return false
+}
+
+// XPReward is the experience a Pokemon earns for defeating opponent.
+func XPReward(opponent pokeapi.PokemonData) int {
+	if opponent.BaseExperience <= 0 {
+		return 10
+	}
+	return opponent.BaseExperience
+}
+
 func SimulateBattle(r *rand.Rand, playerPokemon pokeapi.UserPokemon, opponentPokemon pokeapi.PokemonData) (xpGained int) {
 	fmt.Printf("\n%s--- Battle Start: %s%s%s %s(Lvl %s%d%s)%s %svs %s%s%s %s---%s\n",
 		constants.ColorBrightCyan,
-		constants.ColorGreen, playerPokemon.Name, constants.ColorReset,
+		constants.ColorGreen, playerPokemon.DisplayName(), constants.ColorReset,
 		constants.ColorYellow,
 		constants.ColorBrightCyan, playerPokemon.Level, constants.ColorReset,
 		constants.ColorYellow,
@@ -20,77 +110,21 @@ func SimulateBattle(r *rand.Rand, playerPokemon pokeapi.UserPokemon, opponentPok
 		constants.ColorBrightCyan,
 		constants.ColorReset)
 
-	playerHP, _ := playerPokemon.GetStat("hp")
-	opponentHP, _ := opponentPokemon.GetStat("hp")
-	playerAttack, _ := playerPokemon.GetStat("attack")
-	opponentAttack, _ := opponentPokemon.GetStat("attack")
-	playerDefense, _ := playerPokemon.GetStat("defense")
-	opponentDefense, _ := opponentPokemon.GetStat("defense")
-	playerSpeed, _ := playerPokemon.GetStat("speed")
-	opponentSpeed, _ := opponentPokemon.GetStat("speed")
-
-	turn := 1
-	for playerHP > 0 && opponentHP > 0 {
+	player := NewCombatant(playerPokemon.PokemonData, constants.ColorGreen)
+	opponent := NewCombatant(opponentPokemon, constants.ColorRed)
+
+	for turn := 1; !player.Fainted() && !opponent.Fainted(); turn++ {
 		fmt.Printf("\n%s--- Turn %s%d%s ---%s\n", constants.ColorCyan, constants.ColorYellow, turn, constants.ColorCyan, constants.ColorReset)
 		fmt.Printf("  %s%s HP: %s%d%s | %s%s HP: %s%d%s\n",
-			constants.ColorGreen, playerPokemon.Name, constants.ColorBrightGreen, playerHP, constants.ColorReset,
-			constants.ColorRed, opponentPokemon.Name, constants.ColorBrightRed, opponentHP, constants.ColorReset)
-
-		p1Name := playerPokemon.Name
-		p2Name := opponentPokemon.Name
-
-		var attackerName, defenderName string
-		var attackerHP, defenderHP *int
-		var attackerAtk, defenderAtk int
-		var attackerDef, defenderDef int
-		var attackerColor, defenderColor string // Colors for attacker and defender names
-
-		if opponentSpeed > playerSpeed { // Opponent (p2) is faster
-			attackerName, defenderName = p2Name, p1Name
-			attackerHP, defenderHP = &opponentHP, &playerHP
-			attackerAtk, defenderAtk = opponentAttack, playerAttack
-			attackerDef, defenderDef = opponentDefense, playerDefense
-			attackerColor, defenderColor = constants.ColorRed, constants.ColorGreen // Opponent is Red, Player is Green
-		} else { // Player (p1) is faster or speeds are equal
-			attackerName, defenderName = p1Name, p2Name
-			attackerHP, defenderHP = &playerHP, &opponentHP
-			attackerAtk, defenderAtk = playerAttack, opponentAttack
-			attackerDef, defenderDef = playerDefense, opponentDefense
-			attackerColor, defenderColor = constants.ColorGreen, constants.ColorRed // Player is Green, Opponent is Red
-		}
-
-		// First attacker's move
-		fmt.Printf("  %s%s%s attacks %s%s%s!\n", attackerColor, attackerName, constants.ColorReset, defenderColor, defenderName, constants.ColorReset)
-		damage := CalculateDamage(r, attackerAtk, defenderDef)
-		*defenderHP -= damage
-		fmt.Printf("  %s%s%s takes %s%d%s damage.\n", defenderColor, defenderName, constants.ColorReset, constants.ColorBrightRed, damage, constants.ColorReset)
-
-		if *defenderHP <= 0 {
-			fmt.Printf("  %s%s%s fainted!%s\n", defenderColor, defenderName, constants.ColorRed, constants.ColorReset)
-			break
-		}
-
-		// Second attacker's move (swap roles)
-		// Previous defender is now attacker, previous attacker is now defender
-		fmt.Printf("  %s%s%s attacks %s%s%s!\n", defenderColor, defenderName, constants.ColorReset, attackerColor, attackerName, constants.ColorReset) // Original defender (now attacker) attacks original attacker (now defender)
-		damage = CalculateDamage(r, defenderAtk, attackerDef)                                                                                          // Original defender's attack vs original attacker's defense
-		*attackerHP -= damage
-		fmt.Printf("  %s%s%s takes %s%d%s damage.\n", attackerColor, attackerName, constants.ColorReset, constants.ColorBrightRed, damage, constants.ColorReset)
-
-		if *attackerHP <= 0 {
-			fmt.Printf("  %s%s%s fainted!%s\n", attackerColor, attackerName, constants.ColorRed, constants.ColorReset)
-			break
-		}
-		turn++
+			constants.ColorGreen, player.Name, constants.ColorBrightGreen, player.HP, constants.ColorReset,
+			constants.ColorRed, opponent.Name, constants.ColorBrightRed, opponent.HP, constants.ColorReset)
+		Round(r, &player, &opponent)
 	}
 
 	fmt.Printf("\n%s--- Battle End ---%s\n", constants.ColorBrightCyan, constants.ColorReset)
-	if playerHP > 0 {
-		fmt.Printf("%s%s%s wins!%s\n", constants.ColorBrightGreen, playerPokemon.Name, constants.ColorBrightGreen, constants.ColorReset)
-		xpGained = opponentPokemon.BaseExperience
-		if xpGained <= 0 {
-			xpGained = 10
-		}
+	if !player.Fainted() {
+		fmt.Printf("%s%s%s wins!%s\n", constants.ColorBrightGreen, playerPokemon.DisplayName(), constants.ColorBrightGreen, constants.ColorReset)
+		xpGained = XPReward(opponentPokemon)
 	} else {
 		fmt.Printf("%s%s%s wins!%s\n", constants.ColorBrightRed, opponentPokemon.Name, constants.ColorBrightRed, constants.ColorReset)
 		xpGained = 0
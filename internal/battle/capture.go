@@ -0,0 +1,108 @@
+package battle
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+)
+
+// shakeChecks is how many shake checks a ball must pass to catch a Pokemon. The ball
+// visibly wobbles after each of the first three; passing the fourth is the "click".
+const shakeChecks = 4
+
+// CaptureAttempt holds everything the catch formula looks at for a single throw.
+type CaptureAttempt struct {
+	CaptureRate int     // Species capture_rate, from 3 (legendaries) to 255 (caterpie)
+	MaxHP       int     // Target's maximum HP
+	CurrentHP   int     // Target's remaining HP; the weaker it is, the easier the catch
+	Status      Status  // Target's status condition
+	BallBonus   float64 // Ball multiplier, e.g. 1.5 for a Great Ball
+}
+
+// CaptureResult is the outcome of a throw.
+type CaptureResult struct {
+	Shakes int  // Shake checks passed before the Pokemon broke free, or 4 if caught
+	Caught bool // Whether the Pokemon was caught
+}
+
+// CatchValue computes the modified catch rate "a" of the mainline games:
+//
+//	a = (3*MaxHP - 2*CurrentHP) * CaptureRate * BallBonus / (3*MaxHP) * StatusBonus
+//
+// A value of 255 or more means the Pokemon is caught without any shake checks.
+func CatchValue(a CaptureAttempt) float64 {
+	maxHP := max(a.MaxHP, 1)
+	currentHP := min(max(a.CurrentHP, 1), maxHP)
+	ball := a.BallBonus
+	if ball <= 0 {
+		ball = 1.0
+	}
+
+	value := float64(3*maxHP-2*currentHP) * float64(a.CaptureRate) * ball / float64(3*maxHP)
+	return max(value*a.Status.CatchBonus(), 1)
+}
+
+// ShakeProbability is the chance, out of 65536, that a single shake check succeeds
+// for the catch value a (generation V onwards: b = 65536 / (255/a)^(3/16)).
+func ShakeProbability(a float64) int {
+	if a >= 255 {
+		return 65536
+	}
+	return int(65536 / math.Pow(255/a, 0.1875))
+}
+
+// AttemptCapture throws a ball: it runs up to four shake checks with r and stops at
+// the first one that fails.
+func AttemptCapture(r *rand.Rand, attempt CaptureAttempt) CaptureResult {
+	a := CatchValue(attempt)
+	if a >= 255 {
+		return CaptureResult{Shakes: shakeChecks, Caught: true}
+	}
+
+	b := ShakeProbability(a)
+	for shake := range shakeChecks {
+		if r.Intn(65536) >= b {
+			return CaptureResult{Shakes: shake}
+		}
+	}
+	return CaptureResult{Shakes: shakeChecks, Caught: true}
+}
+
+// BallContext is the situation a ball is thrown in, for balls whose bonus depends on it.
+type BallContext struct {
+	// Turn is the battle turn of the encounter the ball is thrown on: 1 for the first,
+	// with every ball thrown and every battle round fought taking a turn.
+	Turn        int
+	TargetTypes []string // Types of the wild Pokemon, e.g. "water"
+	Night       bool     // Whether it is night time
+}
+
+// TimerBallBonus grows by roughly 0.3 per battle turn, up to 4x from the 11th turn on.
+func TimerBallBonus(ctx BallContext) float64 {
+	turns := max(ctx.Turn-1, 0)
+	return min(1+float64(turns)*1229/4096, 4.0)
+}
+
+// QuickBallBonus is 5x on the first battle turn of an encounter and 1x afterwards.
+func QuickBallBonus(ctx BallContext) float64 {
+	if ctx.Turn <= 1 {
+		return 5.0
+	}
+	return 1.0
+}
+
+// NetBallBonus is 3.5x against Water and Bug types.
+func NetBallBonus(ctx BallContext) float64 {
+	if slices.Contains(ctx.TargetTypes, "water") || slices.Contains(ctx.TargetTypes, "bug") {
+		return 3.5
+	}
+	return 1.0
+}
+
+// DuskBallBonus is 3x at night.
+func DuskBallBonus(ctx BallContext) float64 {
+	if ctx.Night {
+		return 3.0
+	}
+	return 1.0
+}
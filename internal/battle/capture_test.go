@@ -0,0 +1,145 @@
+package battle_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/voidarchive/pokedex/internal/battle"
+)
+
+func TestCatchValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		attempt battle.CaptureAttempt
+		want    float64
+	}{
+		{
+			name:    "Full HP, Poke Ball",
+			attempt: battle.CaptureAttempt{CaptureRate: 255, MaxHP: 45, CurrentHP: 45, BallBonus: 1},
+			want:    85,
+		},
+		{
+			name:    "1 HP left is almost three times easier",
+			attempt: battle.CaptureAttempt{CaptureRate: 255, MaxHP: 45, CurrentHP: 1, BallBonus: 1},
+			want:    (3*45 - 2) * 255 / float64(3*45),
+		},
+		{
+			name:    "Great Ball and paralysis",
+			attempt: battle.CaptureAttempt{CaptureRate: 45, MaxHP: 60, CurrentHP: 60, Status: battle.StatusParalysis, BallBonus: 1.5},
+			want:    45 * 1.5 / 3 * 1.5,
+		},
+		{
+			name:    "Missing ball bonus counts as a Poke Ball",
+			attempt: battle.CaptureAttempt{CaptureRate: 90, MaxHP: 10, CurrentHP: 10},
+			want:    30,
+		},
+		{
+			name:    "Never below 1",
+			attempt: battle.CaptureAttempt{CaptureRate: 0, MaxHP: 10, CurrentHP: 10, BallBonus: 1},
+			want:    1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := battle.CatchValue(tc.attempt); got < tc.want-0.001 || got > tc.want+0.001 {
+				t.Errorf("CatchValue(%+v) = %.3f; want %.3f", tc.attempt, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestShakeProbability(t *testing.T) {
+	if got := battle.ShakeProbability(255); got != 65536 {
+		t.Errorf("ShakeProbability(255) = %d; want 65536", got)
+	}
+	previous := 0
+	for _, a := range []float64{1, 10, 50, 100, 200, 254} {
+		b := battle.ShakeProbability(a)
+		if b <= previous || b >= 65536 {
+			t.Errorf("ShakeProbability(%.0f) = %d; want it to grow with a and stay below 65536", a, b)
+		}
+		previous = b
+	}
+}
+
+// catchRate throws n balls with a fixed seed and returns the fraction that caught.
+func catchRate(attempt battle.CaptureAttempt, n int) float64 {
+	r := rand.New(rand.NewSource(1))
+	caught := 0
+	for range n {
+		if battle.AttemptCapture(r, attempt).Caught {
+			caught++
+		}
+	}
+	return float64(caught) / float64(n)
+}
+
+func TestAttemptCapture(t *testing.T) {
+	const throws = 5000
+
+	caterpie := catchRate(battle.CaptureAttempt{CaptureRate: 255, MaxHP: 45, CurrentHP: 45, BallBonus: 1}, throws)
+	mewtwo := catchRate(battle.CaptureAttempt{CaptureRate: 3, MaxHP: 106, CurrentHP: 106, BallBonus: 1}, throws)
+	weakMewtwo := catchRate(battle.CaptureAttempt{CaptureRate: 3, MaxHP: 106, CurrentHP: 1, Status: battle.StatusFreeze, BallBonus: 2}, throws)
+
+	// Expected rates: (b/65536)^4 with b from ShakeProbability.
+	if caterpie < 0.40 || caterpie > 0.48 {
+		t.Errorf("caterpie catch rate = %.3f; want about 0.44", caterpie)
+	}
+	if mewtwo < 0.005 || mewtwo > 0.03 {
+		t.Errorf("mewtwo catch rate = %.3f; want about 0.016", mewtwo)
+	}
+	if weakMewtwo <= mewtwo*3 {
+		t.Errorf("a frozen mewtwo at 1 HP (%.3f) should be much easier than a healthy one (%.3f)", weakMewtwo, mewtwo)
+	}
+
+	certain := battle.AttemptCapture(rand.New(rand.NewSource(1)), battle.CaptureAttempt{CaptureRate: 255, MaxHP: 45, CurrentHP: 1, Status: battle.StatusFreeze, BallBonus: 1})
+	if !certain.Caught || certain.Shakes != 4 {
+		t.Errorf("a catch value of 255 or more should always catch, got %+v", certain)
+	}
+}
+
+func TestAttemptCaptureShakes(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	seen := map[int]bool{}
+	for range 1000 {
+		result := battle.AttemptCapture(r, battle.CaptureAttempt{CaptureRate: 45, MaxHP: 50, CurrentHP: 50, BallBonus: 1})
+		if result.Caught != (result.Shakes == 4) {
+			t.Fatalf("inconsistent result %+v", result)
+		}
+		seen[result.Shakes] = true
+	}
+	for shakes := range 5 {
+		if !seen[shakes] {
+			t.Errorf("never saw a throw end after %d shakes", shakes)
+		}
+	}
+}
+
+func TestBallBonuses(t *testing.T) {
+	tests := []struct {
+		name  string
+		bonus func(battle.BallContext) float64
+		ctx   battle.BallContext
+		want  float64
+	}{
+		{"Timer Ball, first turn", battle.TimerBallBonus, battle.BallContext{Turn: 1}, 1},
+		{"Timer Ball, fifth turn", battle.TimerBallBonus, battle.BallContext{Turn: 5}, 1 + 4*1229/4096.0},
+		{"Timer Ball, capped", battle.TimerBallBonus, battle.BallContext{Turn: 30}, 4},
+		{"Quick Ball, first turn", battle.QuickBallBonus, battle.BallContext{Turn: 1}, 5},
+		{"Quick Ball, later", battle.QuickBallBonus, battle.BallContext{Turn: 2}, 1},
+		{"Net Ball, water", battle.NetBallBonus, battle.BallContext{TargetTypes: []string{"water"}}, 3.5},
+		{"Net Ball, bug/flying", battle.NetBallBonus, battle.BallContext{TargetTypes: []string{"bug", "flying"}}, 3.5},
+		{"Net Ball, fire", battle.NetBallBonus, battle.BallContext{TargetTypes: []string{"fire"}}, 1},
+		{"Dusk Ball, night", battle.DuskBallBonus, battle.BallContext{Night: true}, 3},
+		{"Dusk Ball, day", battle.DuskBallBonus, battle.BallContext{}, 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.bonus(tc.ctx); got < tc.want-0.001 || got > tc.want+0.001 {
+				t.Errorf("bonus = %.3f; want %.3f", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package battle
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/voidarchive/pokedex/internal/shared/constants"
+)
+
+// Status is a non-volatile status condition, such as a burn. It lasts between battle
+// rounds and makes a wild Pokemon easier to catch.
+type Status string
+
+const (
+	StatusNone      Status = ""
+	StatusFreeze    Status = "freeze"
+	StatusParalysis Status = "paralysis"
+	StatusBurn      Status = "burn"
+	StatusPoison    Status = "poison"
+)
+
+// CatchBonus is the status multiplier of the catch formula: frozen Pokemon are the
+// easiest to catch. There's no sleep: battles only use damaging attacks, and sleep
+// comes from status moves such as Sleep Powder.
+func (s Status) CatchBonus() float64 {
+	switch s {
+	case StatusFreeze:
+		return 2.5
+	case StatusParalysis, StatusBurn, StatusPoison:
+		return 1.5
+	default:
+		return 1.0
+	}
+}
+
+// statusChance is the percent chance that an attack inflicts its type's status, like
+// the 10% burn of Ember.
+const statusChance = 10
+
+// typeStatuses are the statuses attacks of a type can inflict, as the damaging moves of
+// that type do: Ember burns, Thunder Shock paralyzes, Poison Sting poisons and Ice Beam
+// freezes.
+var typeStatuses = map[string]Status{
+	"fire":     StatusBurn,
+	"electric": StatusParalysis,
+	"poison":   StatusPoison,
+	"ice":      StatusFreeze,
+}
+
+// immune reports whether c can't get status: a Pokemon can't get the status of its own
+// type, and Steel types can't be poisoned either.
+func (c Combatant) immune(status Status) bool {
+	for _, t := range c.Types {
+		if typeStatuses[t] == status || t == "steel" && status == StatusPoison {
+			return true
+		}
+	}
+	return false
+}
+
+// inflictStatus gives defender, hit by attacker, the status of one of attacker's types
+// with statusChance percent for each type, in order. A Pokemon only has one status at
+// a time.
+func inflictStatus(r *rand.Rand, attacker, defender *Combatant) {
+	if defender.Status != StatusNone {
+		return
+	}
+	for _, t := range attacker.Types {
+		status, ok := typeStatuses[t]
+		if !ok || defender.immune(status) {
+			continue
+		}
+		if r.Intn(100) < statusChance {
+			defender.Status = status
+			fmt.Printf("  %s%s%s %s%s%s!\n", defender.Color, defender.Name, constants.ColorReset, constants.ColorPurple, statusInflicted[status], constants.ColorReset)
+			return
+		}
+	}
+}
+
+var statusInflicted = map[Status]string{
+	StatusFreeze:    "was frozen solid",
+	StatusParalysis: "is paralyzed",
+	StatusBurn:      "was burned",
+	StatusPoison:    "was poisoned",
+}
+
+// canMove reports whether c's status lets it attack this turn. A frozen Pokemon thaws
+// with some chance each turn; a paralyzed one can't move one turn in four.
+func canMove(r *rand.Rand, c *Combatant) bool {
+	switch c.Status {
+	case StatusFreeze:
+		if r.Intn(5) == 0 {
+			c.Status = StatusNone
+			fmt.Printf("  %s%s%s thawed out!\n", c.Color, c.Name, constants.ColorReset)
+			return true
+		}
+		fmt.Printf("  %s%s%s is frozen solid!\n", c.Color, c.Name, constants.ColorReset)
+		return false
+	case StatusParalysis:
+		if r.Intn(4) == 0 {
+			fmt.Printf("  %s%s%s is paralyzed! It can't move!\n", c.Color, c.Name, constants.ColorReset)
+			return false
+		}
+	}
+	return true
+}
+
+// statusDamage hurts a burned Pokemon by 1/16 of its max HP and a poisoned one by 1/8
+// at the end of a round, and reports whether it fainted.
+func statusDamage(c *Combatant) bool {
+	var damage int
+	switch c.Status {
+	case StatusBurn:
+		damage = max(c.MaxHP/16, 1)
+		fmt.Printf("  %s%s%s is hurt by its burn.\n", c.Color, c.Name, constants.ColorReset)
+	case StatusPoison:
+		damage = max(c.MaxHP/8, 1)
+		fmt.Printf("  %s%s%s is hurt by poison.\n", c.Color, c.Name, constants.ColorReset)
+	default:
+		return false
+	}
+	c.HP -= damage
+	if c.Fainted() {
+		fmt.Printf("  %s%s%s fainted!%s\n", c.Color, c.Name, constants.ColorRed, constants.ColorReset)
+		return true
+	}
+	return false
+}
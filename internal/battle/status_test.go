@@ -0,0 +1,90 @@
+package battle_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/voidarchive/pokedex/internal/battle"
+)
+
+// sturdy is a combatant that takes many rounds to knock out.
+func sturdy(name string, types ...string) battle.Combatant {
+	return battle.Combatant{Name: name, HP: 500, MaxHP: 500, Attack: 1, Defense: 500, Speed: 10, Types: types}
+}
+
+func TestAttacksInflictTheirTypesStatus(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tests := []struct {
+		attacker, defender []string
+		want               battle.Status
+	}{
+		{attacker: []string{"fire"}, defender: []string{"bug"}, want: battle.StatusBurn},
+		{attacker: []string{"electric"}, defender: []string{"water"}, want: battle.StatusParalysis},
+		{attacker: []string{"poison"}, defender: []string{"normal"}, want: battle.StatusPoison},
+		{attacker: []string{"ice"}, defender: []string{"grass"}, want: battle.StatusFreeze},
+		{attacker: []string{"fire"}, defender: []string{"fire"}, want: battle.StatusNone},
+		{attacker: []string{"poison"}, defender: []string{"steel"}, want: battle.StatusNone},
+		{attacker: []string{"psychic"}, defender: []string{"bug"}, want: battle.StatusNone},
+	}
+
+	for _, tc := range tests {
+		player, opponent := sturdy("player", tc.attacker...), sturdy("opponent", tc.defender...)
+		player.Speed = 20
+		for range 100 {
+			battle.Round(r, &player, &opponent)
+			if opponent.Status != battle.StatusNone {
+				break
+			}
+		}
+		if opponent.Status != tc.want {
+			t.Errorf("%v attacks on %v inflicted %q; want %q", tc.attacker, tc.defender, opponent.Status, tc.want)
+		}
+	}
+}
+
+func TestDualTypeAttacksRollForEachType(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	inflicted := make(map[battle.Status]int)
+	for range 200 {
+		player, opponent := sturdy("player", "fire", "electric"), sturdy("opponent", "water")
+		player.Speed = 20
+		for opponent.Status == battle.StatusNone {
+			battle.Round(r, &player, &opponent)
+		}
+		inflicted[opponent.Status]++
+	}
+	// The fire type is rolled for first, so burns are a little more common.
+	if inflicted[battle.StatusBurn] == 0 || inflicted[battle.StatusParalysis] == 0 || len(inflicted) != 2 {
+		t.Errorf("fire/electric attacks inflicted %v; want both burns and paralysis", inflicted)
+	}
+}
+
+func TestBurnAndPoisonHurtAtTheEndOfTheRound(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	player := battle.Combatant{Name: "player", HP: 80, MaxHP: 80, Attack: 1, Defense: 1000, Speed: 20, Status: battle.StatusPoison}
+	opponent := battle.Combatant{Name: "opponent", HP: 160, MaxHP: 160, Attack: 0, Defense: 1000, Speed: 10, Status: battle.StatusBurn}
+
+	battle.Round(r, &player, &opponent)
+	// Each side takes the 1 damage of the other's attack, then 1/8 (poison) or 1/16
+	// (burn) of its max HP.
+	if player.HP != 80-1-10 || opponent.HP != 160-1-10 {
+		t.Errorf("HP after a round = %d and %d; want %d and %d", player.HP, opponent.HP, 80-1-10, 160-1-10)
+	}
+}
+
+func TestFrozenCombatantCantAttack(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	player, opponent := sturdy("player"), sturdy("opponent")
+	player.Status = battle.StatusFreeze
+
+	for range 20 {
+		before := opponent.HP
+		battle.Round(r, &player, &opponent)
+		if player.Status == battle.StatusFreeze && opponent.HP != before {
+			t.Fatalf("a frozen combatant attacked: opponent HP %d -> %d", before, opponent.HP)
+		}
+	}
+	if player.Status != battle.StatusNone {
+		t.Errorf("player should have thawed out within 20 rounds, status %q", player.Status)
+	}
+}
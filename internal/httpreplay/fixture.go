@@ -0,0 +1,103 @@
+// Package httpreplay records HTTP request/response pairs to fixture files and
+// serves them back, so code that talks to PokeAPI can be tested offline.
+package httpreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RecordEnvVar is the environment variable that switches FromEnv into recording mode.
+const RecordEnvVar = "HTTPREPLAY_RECORD"
+
+// Fixture is the on-disk representation of one request/response pair.
+type Fixture struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+type FixtureRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+type FixtureResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	// Body holds JSON bodies verbatim so fixtures stay readable and diffs of re-recordings show what changed.
+	Body json.RawMessage `json:"body,omitempty"`
+	// BodyText is used instead of Body when the response isn't valid JSON (e.g. "Not Found").
+	BodyText string `json:"body_text,omitempty"`
+}
+
+// body returns the response body stored in the fixture. JSON bodies are compacted
+// again since MarshalIndent re-indents them when the fixture is written.
+func (r FixtureResponse) body() []byte {
+	if len(r.Body) > 0 {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, r.Body); err == nil {
+			return buf.Bytes()
+		}
+		return r.Body
+	}
+	return []byte(r.BodyText)
+}
+
+// FixtureName returns the file name used to store the fixture for a request.
+// The name is derived from the method and URL so fixtures are easy to find by eye,
+// e.g. "get_pokeapi.co_api_v2_pokemon_pikachu.json".
+func FixtureName(method, rawURL string) string {
+	name := strings.ToLower(method) + "_" + strings.TrimPrefix(strings.TrimPrefix(rawURL, "https://"), "http://")
+	name = strings.TrimRight(name, "/")
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String() + ".json"
+}
+
+func readFixture(path string) (Fixture, error) {
+	var f Fixture
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return f, err
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("httpreplay: malformed fixture %s: %w", path, err)
+	}
+	return f, nil
+}
+
+func writeFixture(dir string, f Fixture) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("httpreplay: could not create fixture dir %s: %w", dir, err)
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("httpreplay: could not marshal fixture: %w", err)
+	}
+	path := filepath.Join(dir, FixtureName(f.Request.Method, f.Request.URL))
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// FromEnv returns a Recorder that hits the real network when HTTPREPLAY_RECORD is set,
+// and a Replayer otherwise. Tests call it so the same test can refresh its own fixtures:
+//
+//	HTTPREPLAY_RECORD=1 go test ./internal/pokeapi/...
+func FromEnv(dir string) http.RoundTripper {
+	if os.Getenv(RecordEnvVar) != "" {
+		return NewRecorder(dir, nil)
+	}
+	return NewReplayer(dir)
+}
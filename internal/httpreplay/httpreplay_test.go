@@ -0,0 +1,89 @@
+package httpreplay_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/voidarchive/pokedex/internal/httpreplay"
+)
+
+func TestRecordThenReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pokemon/pikachu":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"name":"pikachu"}`)
+		default:
+			http.Error(w, "Not Found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	recordingClient := http.Client{Transport: httpreplay.NewRecorder(dir, nil)}
+	for _, path := range []string{"/pokemon/pikachu", "/pokemon/missingno"} {
+		res, err := recordingClient.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("recording %s: %v", path, err)
+		}
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+	}
+	server.Close() // Replay must not need the server any more.
+
+	replayClient := http.Client{Transport: httpreplay.NewReplayer(dir)}
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{path: "/pokemon/pikachu", wantStatus: http.StatusOK, wantBody: `{"name":"pikachu"}`},
+		{path: "/pokemon/missingno", wantStatus: http.StatusNotFound, wantBody: "Not Found\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			res, err := replayClient.Get(server.URL + tc.path)
+			if err != nil {
+				t.Fatalf("replaying %s: %v", tc.path, err)
+			}
+			defer res.Body.Close()
+			body, _ := io.ReadAll(res.Body)
+			if res.StatusCode != tc.wantStatus {
+				t.Errorf("status = %d; want %d", res.StatusCode, tc.wantStatus)
+			}
+			if string(body) != tc.wantBody {
+				t.Errorf("body = %q; want %q", string(body), tc.wantBody)
+			}
+		})
+	}
+}
+
+func TestReplayMissingFixture(t *testing.T) {
+	replayClient := http.Client{Transport: httpreplay.NewReplayer(t.TempDir())}
+	_, err := replayClient.Get("https://pokeapi.co/api/v2/pokemon/pikachu")
+	if !errors.Is(err, httpreplay.ErrNoFixture) {
+		t.Fatalf("expected ErrNoFixture, got %v", err)
+	}
+}
+
+func TestFixtureName(t *testing.T) {
+	tests := []struct {
+		method string
+		url    string
+		want   string
+	}{
+		{"GET", "https://pokeapi.co/api/v2/pokemon/pikachu", "get_pokeapi.co_api_v2_pokemon_pikachu.json"},
+		{"GET", "https://pokeapi.co/api/v2/evolution-chain/10/", "get_pokeapi.co_api_v2_evolution-chain_10.json"},
+		{"GET", "https://pokeapi.co/api/v2/location-area?offset=20&limit=20", "get_pokeapi.co_api_v2_location-area_offset_20_limit_20.json"},
+	}
+	for _, tc := range tests {
+		if got := httpreplay.FixtureName(tc.method, tc.url); got != tc.want {
+			t.Errorf("FixtureName(%s, %s) = %s; want %s", tc.method, tc.url, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package httpreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Recorder is an http.RoundTripper that forwards requests to another transport
+// and saves every request/response pair as a fixture file in Dir.
+type Recorder struct {
+	Dir  string
+	next http.RoundTripper
+}
+
+// NewRecorder creates a Recorder writing to dir. If next is nil, http.DefaultTransport is used.
+func NewRecorder(dir string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{Dir: dir, next: next}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("httpreplay: failed to read response for %s: %w", req.URL, err)
+	}
+
+	fixtureRes := FixtureResponse{
+		StatusCode: res.StatusCode,
+		Header:     res.Header.Clone(),
+	}
+	if json.Valid(body) {
+		fixtureRes.Body = body
+	} else {
+		fixtureRes.BodyText = string(body)
+	}
+	fixture := Fixture{
+		Request:  FixtureRequest{Method: req.Method, URL: req.URL.String()},
+		Response: fixtureRes,
+	}
+	if err := writeFixture(r.Dir, fixture); err != nil {
+		return nil, err
+	}
+
+	// Hand the caller a fresh body since we consumed the original one.
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	return res, nil
+}
@@ -0,0 +1,53 @@
+package httpreplay
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+)
+
+// ErrNoFixture is returned (wrapped) by a Replayer when a request has no recorded fixture.
+var ErrNoFixture = errors.New("httpreplay: no fixture recorded")
+
+// Replayer is an http.RoundTripper that serves responses from fixture files in Dir
+// and never touches the network.
+type Replayer struct {
+	Dir string
+}
+
+// NewReplayer creates a Replayer reading fixtures from dir.
+func NewReplayer(dir string) *Replayer {
+	return &Replayer{Dir: dir}
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := filepath.Join(r.Dir, FixtureName(req.Method, req.URL.String()))
+	fixture, err := readFixture(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w for %s %s (expected %s; re-run with %s=1 to record it)", ErrNoFixture, req.Method, req.URL, path, RecordEnvVar)
+		}
+		return nil, err
+	}
+
+	body := fixture.Response.body()
+	header := fixture.Response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Response.StatusCode, http.StatusText(fixture.Response.StatusCode)),
+		StatusCode:    fixture.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
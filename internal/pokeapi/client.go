@@ -1,13 +1,18 @@
 package pokeapi
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"slices"
+	"time"
 
+	"github.com/voidarchive/pokedex/internal/pokecache"
 	"github.com/voidarchive/pokedex/internal/shared/constants"
+	"github.com/voidarchive/pokedex/internal/shared/logging"
 )
 
 const BaseURL = "https://pokeapi.co/api/v2"
@@ -24,10 +29,6 @@ type LocationAreasResponse struct {
 	Results  []LocationArea `json:"results"`
 }
 
-type PokemonEncounter struct {
-	Pokemon Pokemon `json:"pokemon"`
-}
-
 type Pokemon struct {
 	Name string `json:"name"`
 	URL  string `json:"url"`
@@ -36,6 +37,7 @@ type Pokemon struct {
 type LocationAreaDetail struct {
 	ID                int                `json:"id"`
 	Name              string             `json:"name"`
+	Location          NamedAPIResource   `json:"location"` // The location the area belongs to
 	PokemonEncounters []PokemonEncounter `json:"pokemon_encounters"`
 }
 
@@ -45,7 +47,9 @@ type PokemonData struct {
 	BaseExperience int    `json:"base_experience"`
 	Height         int    `json:"height"`
 	Weight         int    `json:"weight"`
-	Stats          []struct {
+	// Species links to the /pokemon-species entry. It differs from Name for alternate forms (e.g. "deoxys-attack").
+	Species NamedAPIResource `json:"species"`
+	Stats   []struct {
 		BaseStat int `json:"base_stat"`
 		Effort   int `json:"effort"`
 		Stat     struct {
@@ -60,17 +64,62 @@ type PokemonData struct {
 			URL  string `json:"url"`
 		} `json:"type"`
 	} `json:"types"`
+	Moves []PokemonMove `json:"moves,omitempty"` // Learnset; left out of owned Pokemon, see NewUserPokemon
 }
 
 type UserPokemon struct {
-	PokemonData           // Embeds all fields from PokemonData (Name, Stats, Types, etc.)
-	Level           int   `json:"level"`
-	CurrentXP       int   `json:"current_xp"`
-	XPToNextLevel   int   `json:"xp_to_next_level"`
-	CaughtTimestamp int64 `json:"caught_timestamp"` // Unix nanoseconds when caught
+	PokemonData                       // Embeds all fields from PokemonData (Name, Stats, Types, etc.)
+	UID             int               `json:"uid,omitempty"`      // Identifies this individual Pokemon; unlike ID (the species), it survives evolution
+	Nickname        string            `json:"nickname,omitempty"` // Name the player gave it, if any
+	Level           int               `json:"level"`
+	CurrentXP       int               `json:"current_xp"`
+	XPToNextLevel   int               `json:"xp_to_next_level"`
+	CaughtTimestamp int64             `json:"caught_timestamp"`     // Unix nanoseconds when caught
+	Gender          string            `json:"gender,omitempty"`     // "female", "male", or "" for genderless
+	HeldItem        string            `json:"held_item,omitempty"`  // Item the Pokemon holds, e.g. "metal-coat"
+	Happiness       int               `json:"happiness"`            // 0 to MaxHappiness, see ChangeHappiness
+	Evolutions      []EvolutionRecord `json:"evolutions,omitempty"` // Every evolution since it was caught, oldest first
 	// TODO: Potentially add other fields later, like current HP, status conditions, moveset, etc.
 }
 
+// DisplayName is what the player calls the Pokemon: its nickname, or else its species.
+func (up UserPokemon) DisplayName() string {
+	if up.Nickname != "" {
+		return up.Nickname
+	}
+	return up.Name
+}
+
+// EvolutionRecord is one evolution in the history of an owned Pokemon.
+type EvolutionRecord struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Trigger   string `json:"trigger"`        // e.g. "level-up" or "use-item"
+	Item      string `json:"item,omitempty"` // Item used, for "use-item"
+	Level     int    `json:"level"`
+	Timestamp int64  `json:"timestamp"` // Unix nanoseconds
+}
+
+// NewUserPokemon returns a newly owned Pokemon of the species described by data, at
+// level. The learnset is dropped: it's large, only needed to check evolutions, and can
+// always be fetched again.
+func NewUserPokemon(data PokemonData, level int) UserPokemon {
+	data.Moves = nil
+	up := UserPokemon{PokemonData: data, Level: level}
+	up.XPToNextLevel = up.CalculateNewXPToNextLevel()
+	return up
+}
+
+// EvolveInto returns the Pokemon after evolving into the species described by data.
+// Only the species changes: the individual keeps its UID, level, XP, catch date and
+// everything else, and record is added to its evolution history.
+func (up UserPokemon) EvolveInto(data PokemonData, record EvolutionRecord) UserPokemon {
+	data.Moves = nil
+	up.PokemonData = data
+	up.Evolutions = append(slices.Clip(up.Evolutions), record)
+	return up
+}
+
 // CalculateNewXPToNextLevel provides a basic formula for determining XP for the next level.
 // This can be adjusted for different leveling curves.
 // Exported for potential use elsewhere if needed, but primarily a helper for AddXP.
@@ -92,7 +141,7 @@ func (up *UserPokemon) AddXP(xpGained int) bool {
 	}
 
 	// Use BrightGreen for the Pokemon's name and Yellow for the XP amount.
-	fmt.Printf("%s%s%s gained %s%d%s XP!\n", constants.ColorBrightGreen, up.Name, constants.ColorReset, constants.ColorYellow, xpGained, constants.ColorReset)
+	fmt.Printf("%s%s%s gained %s%d%s XP!\n", constants.ColorBrightGreen, up.DisplayName(), constants.ColorReset, constants.ColorYellow, xpGained, constants.ColorReset)
 	up.CurrentXP += xpGained
 	var leveledUpThisCycle bool = false
 	var anyLevelUp bool = false
@@ -107,7 +156,7 @@ func (up *UserPokemon) AddXP(xpGained int) bool {
 		// Use BrightPurple for the congratulations message, BrightGreen for name, BrightCyan for level and new XP status.
 		fmt.Printf("%sCongratulations! %s%s%s grew to Level %s%d%s! (XP: %s%d%s/%s%d%s)\n",
 			constants.ColorBrightPurple,
-			constants.ColorBrightGreen, up.Name, constants.ColorReset,
+			constants.ColorBrightGreen, up.DisplayName(), constants.ColorReset,
 			constants.ColorBrightCyan, up.Level, constants.ColorReset,
 			constants.ColorBrightCyan, up.CurrentXP, constants.ColorReset,
 			constants.ColorCyan, up.XPToNextLevel, constants.ColorReset)
@@ -125,6 +174,15 @@ func (up *UserPokemon) AddXP(xpGained int) bool {
 	return anyLevelUp
 }
 
+// SpeciesName returns the name of the Pokemon's species, e.g. "deoxys" for the
+// "deoxys-attack" form. Saves from before species links were stored only have the name.
+func (pd *PokemonData) SpeciesName() string {
+	if pd.Species.Name == "" {
+		return pd.Name
+	}
+	return pd.Species.Name
+}
+
 // GetStat retrieves a specific stat value by name for a Pokemon.
 // Returns the stat value and true if found, otherwise 0 and false.
 func (pd *PokemonData) GetStat(statName string) (int, bool) {
@@ -141,180 +199,94 @@ type CacheInterface interface {
 	Get(key string) ([]byte, bool)
 }
 
+// ValidatingCache is implemented by caches that keep stale entries around together with
+// their ETag / Last-Modified validators. When the client's cache implements it, stale
+// entries are revalidated with a conditional request instead of being downloaded again.
+type ValidatingCache interface {
+	CacheInterface
+	AddWithValidators(key string, val []byte, validators pokecache.Validators)
+	GetStale(key string) ([]byte, pokecache.Validators, bool)
+	Refresh(key string) bool
+}
+
+// Client is safe for concurrent use. Concurrent requests for the same URL are
+// coalesced into a single HTTP call whose decoded result all callers share.
 type Client struct {
 	httpClient http.Client
 	cache      CacheInterface
+	flights    *flightGroup
+	logger     *slog.Logger
 }
 
-func NewClient(cache CacheInterface) Client {
-	return Client{
-		httpClient: http.Client{},
-		cache:      cache,
+// ClientOption customizes a Client created by NewClient.
+type ClientOption func(*Client)
+
+// WithTransport makes the client send its requests through rt instead of
+// http.DefaultTransport. Tests use it to plug in record/replay transports.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.httpClient.Transport = rt
 	}
 }
 
-func (c *Client) FetchLocationAreas(url string) (LocationAreasResponse, error) {
-	var emptyResponse LocationAreasResponse
-
-	if url == "" {
-		return emptyResponse, fmt.Errorf("cannot fetch from empty URL")
-	}
-	if cacheData, ok := c.cache.Get(url); ok {
-		log.Printf("Cache hit for URL: %s\n", url)
-		var locationAreasRes LocationAreasResponse
-		if err := json.Unmarshal(cacheData, &locationAreasRes); err != nil {
-			log.Printf("Error unmarshalling cached data for %s: %v.", url, err)
-		} else {
-			return locationAreasRes, nil
-		}
+// WithLogger makes the client log every request at debug level, with the URL,
+// status, duration and whether it was served from the cache. Without it the client is silent.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
 	}
+}
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return emptyResponse, err
+func NewClient(cache CacheInterface, opts ...ClientOption) Client {
+	c := Client{
+		httpClient: http.Client{},
+		cache:      cache,
+		flights:    newFlightGroup(),
+		logger:     logging.Discard(),
 	}
-	res, err := c.httpClient.Do(req)
-	if err != nil {
-		return emptyResponse, err
+	for _, opt := range opts {
+		opt(&c)
 	}
+	return c
+}
 
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return emptyResponse, fmt.Errorf("failed to read response: %w", err)
+func (c *Client) FetchLocationAreas(url string) (LocationAreasResponse, error) {
+	if url == "" {
+		return LocationAreasResponse{}, fmt.Errorf("cannot fetch from empty URL")
 	}
 
-	// Cache the response
-	c.cache.Add(url, body)
-
-	var locationAreasRes LocationAreasResponse
-	if err := json.Unmarshal(body, &locationAreasRes); err != nil {
-		return emptyResponse, fmt.Errorf("failed to unmarshal JSON: %w", err)
-	}
-	return locationAreasRes, nil
+	return coalesce(c, url, func() (LocationAreasResponse, error) {
+		body, err := c.get(url)
+		if err != nil {
+			return LocationAreasResponse{}, fmt.Errorf("request for location areas failed: %w", err)
+		}
+		return decode[LocationAreasResponse](url, body)
+	})
 }
 
 func (c *Client) FetchLocationAreaDetail(areaName string) (LocationAreaDetail, error) {
-	var emptyResponse LocationAreaDetail
-
 	url := fmt.Sprintf("%s/location-area/%s", BaseURL, areaName)
 
-	// Check cache first
-	if cacheData, ok := c.cache.Get(url); ok {
-		log.Printf("Cache hit for URL: %s\n", url)
-		var locationAreaDetail LocationAreaDetail
-		if err := json.Unmarshal(cacheData, &locationAreaDetail); err != nil {
-			log.Printf("Error unmarshalling cached data for %s: %v.", url, err)
-		} else {
-			return locationAreaDetail, nil
+	return coalesce(c, url, func() (LocationAreaDetail, error) {
+		body, err := c.get(url)
+		if err != nil {
+			return LocationAreaDetail{}, resourceError("location area", areaName, err)
 		}
-	}
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return emptyResponse, err
-	}
-
-	res, err := c.httpClient.Do(req)
-	if err != nil {
-		return emptyResponse, err
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode == http.StatusNotFound {
-		return emptyResponse, fmt.Errorf("location area '%s' not found", areaName)
-	}
-
-	if res.StatusCode > 299 { // Broader check for non-2xx status codes
-		return emptyResponse, fmt.Errorf("API request failed with status: %d for URL %s", res.StatusCode, url)
-	}
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return emptyResponse, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	// Cache the response
-	c.cache.Add(url, body)
-
-	var locationAreaDetail LocationAreaDetail
-	if err := json.Unmarshal(body, &locationAreaDetail); err != nil {
-		return emptyResponse, fmt.Errorf("failed to unmarshal JSON: %w", err)
-	}
-
-	return locationAreaDetail, nil
+		return decode[LocationAreaDetail](url, body)
+	})
 }
 
 // FetchPokemon retrieves detailed information about a specific Pokemon by its name.
 func (c *Client) FetchPokemon(pokemonName string) (PokemonData, error) {
-	var emptyResponse PokemonData
 	url := fmt.Sprintf("%s/pokemon/%s", BaseURL, pokemonName)
 
-	if data, ok := c.cache.Get(url); ok {
-		log.Printf("Cache hit for Pokemon: %s\n", pokemonName)
-		var pokemonData PokemonData
-		if err := json.Unmarshal(data, &pokemonData); err != nil {
-			return emptyResponse, fmt.Errorf("failed to unmarshal cached pokemon data for %s: %w", pokemonName, err)
+	return coalesce(c, url, func() (PokemonData, error) {
+		body, err := c.get(url)
+		if err != nil {
+			return PokemonData{}, resourceError("pokemon", pokemonName, err)
 		}
-		return pokemonData, nil
-	}
-	log.Printf("Cache miss for Pokemon: %s. Fetching from API: %s\n", pokemonName, url)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return emptyResponse, fmt.Errorf("could not create request for %s: %w", pokemonName, err)
-	}
-
-	res, err := c.httpClient.Do(req)
-	if err != nil {
-		return emptyResponse, fmt.Errorf("request to fetch %s failed: %w", pokemonName, err)
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode == http.StatusNotFound {
-		return emptyResponse, fmt.Errorf("pokemon '%s' not found", pokemonName)
-	}
-	if res.StatusCode > 299 {
-		bodyBytes, _ := io.ReadAll(res.Body) // Try to read body for more error info
-		return emptyResponse, fmt.Errorf("API request for %s failed with status %d: %s", pokemonName, res.StatusCode, string(bodyBytes))
-	}
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return emptyResponse, fmt.Errorf("failed to read response body for %s: %w", pokemonName, err)
-	}
-
-	var pokemonData PokemonData
-	if err := json.Unmarshal(body, &pokemonData); err != nil {
-		return emptyResponse, fmt.Errorf("failed to unmarshal pokemon data for %s: %w. Body: %s", pokemonName, err, string(body))
-	}
-
-	c.cache.Add(url, body)
-	return pokemonData, nil
-}
-
-// PokemonSpecies represents data from the /pokemon-species/{id_or_name}/ endpoint.
-// We primarily need it to get the URL for the evolution chain.
-type PokemonSpecies struct {
-	ID             int    `json:"id"`
-	Name           string `json:"name"`
-	EvolutionChain struct {
-		URL string `json:"url"`
-	} `json:"evolution_chain"`
-	// Other fields like flavor_text_entries, generation, etc., are available but not immediately needed.
-}
-
-// EvolutionDetail contains the specifics of how a Pokemon evolves, e.g., min_level.
-// There can be multiple evolution details (e.g., level up + hold item), so it's an array.
-type EvolutionDetail struct {
-	MinLevel *int `json:"min_level"` // Pointer because it can be null
-	Trigger  struct {
-		Name string `json:"name"`
-	} `json:"trigger"` // e.g., "level-up"
-	// Other details: item, gender, held_item, known_move, known_move_type, location, min_affection,
-	// min_beauty, min_happiness, needs_overworld_rain, party_species, party_type, relative_physical_stats,
-	// time_of_day, trade_species, turn_upside_down. For now, we only care about min_level and level-up trigger.
+		return decode[PokemonData](url, body)
+	})
 }
 
 // ChainLink represents one link in an evolution chain.
@@ -353,98 +325,107 @@ type EvolutionChainResponse struct {
 }
 
 func (c *Client) FetchPokemonSpecies(pokemonNameOrID string) (PokemonSpecies, error) {
-	var emptyResponse PokemonSpecies
 	url := fmt.Sprintf("%s/pokemon-species/%s", BaseURL, pokemonNameOrID)
 
-	if data, ok := c.cache.Get(url); ok {
-		log.Printf("Cache hit for Pokemon Species: %s\\n", pokemonNameOrID)
-		var speciesData PokemonSpecies
-		if err := json.Unmarshal(data, &speciesData); err != nil {
-			return emptyResponse, fmt.Errorf("failed to unmarshal cached species data for %s: %w", pokemonNameOrID, err)
+	return coalesce(c, url, func() (PokemonSpecies, error) {
+		body, err := c.get(url)
+		if err != nil {
+			return PokemonSpecies{}, resourceError("pokemon species", pokemonNameOrID, err)
 		}
-		return speciesData, nil
-	}
-	log.Printf("Cache miss for Pokemon Species: %s. Fetching from API: %s\\n", pokemonNameOrID, url)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return emptyResponse, fmt.Errorf("could not create request for species %s: %w", pokemonNameOrID, err)
-	}
-
-	res, err := c.httpClient.Do(req)
-	if err != nil {
-		return emptyResponse, fmt.Errorf("request to fetch species %s failed: %w", pokemonNameOrID, err)
-	}
-	defer res.Body.Close()
+		return decode[PokemonSpecies](url, body)
+	})
+}
 
-	if res.StatusCode == http.StatusNotFound {
-		return emptyResponse, fmt.Errorf("pokemon species '%s' not found", pokemonNameOrID)
-	}
-	if res.StatusCode > 299 {
-		bodyBytes, _ := io.ReadAll(res.Body)
-		return emptyResponse, fmt.Errorf("API request for species %s failed with status %d: %s", pokemonNameOrID, res.StatusCode, string(bodyBytes))
+func (c *Client) FetchEvolutionChain(url string) (EvolutionChainResponse, error) {
+	if url == "" {
+		return EvolutionChainResponse{}, fmt.Errorf("cannot fetch evolution chain from empty URL")
 	}
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return emptyResponse, fmt.Errorf("failed to read response body for species %s: %w", pokemonNameOrID, err)
-	}
+	return coalesce(c, url, func() (EvolutionChainResponse, error) {
+		body, err := c.get(url)
+		if err != nil {
+			return EvolutionChainResponse{}, resourceError("evolution chain", url, err)
+		}
+		return decode[EvolutionChainResponse](url, body)
+	})
+}
 
-	var speciesData PokemonSpecies
-	if err := json.Unmarshal(body, &speciesData); err != nil {
-		return emptyResponse, fmt.Errorf("failed to unmarshal species data for %s: %w. Body: %s", pokemonNameOrID, err, string(body))
+// resourceError adds the kind and name of the requested resource to an error from get,
+// keeping the typed error underneath for errors.Is / errors.As.
+func resourceError(kind, name string, err error) error {
+	if errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("%s '%s' not found: %w", kind, name, err)
 	}
-
-	c.cache.Add(url, body)
-	return speciesData, nil
+	return fmt.Errorf("request for %s '%s' failed: %w", kind, name, err)
 }
 
-func (c *Client) FetchEvolutionChain(url string) (EvolutionChainResponse, error) {
-	var emptyResponse EvolutionChainResponse
-
-	if url == "" {
-		return emptyResponse, fmt.Errorf("cannot fetch evolution chain from empty URL")
-	}
+// get returns the response body for url. Fresh cache entries are served without a
+// request. If the cache still holds a stale copy with validators, the request is made
+// conditional, and a 304 Not Modified answer refreshes that copy instead of downloading
+// the body again. Only 2xx bodies are cached; other statuses come back as *HTTPError and
+// transport failures wrap ErrNetwork.
+func (c *Client) get(url string) ([]byte, error) {
+	return c.getContext(context.Background(), url)
+}
 
+// getContext is get with a context that cancels the HTTP request.
+func (c *Client) getContext(ctx context.Context, url string) ([]byte, error) {
+	start := time.Now()
 	if data, ok := c.cache.Get(url); ok {
-		log.Printf("Cache hit for Evolution Chain URL: %s\\n", url)
-		var chainData EvolutionChainResponse
-		if err := json.Unmarshal(data, &chainData); err != nil {
-			return emptyResponse, fmt.Errorf("failed to unmarshal cached evolution chain data for %s: %w", url, err)
-		}
-		return chainData, nil
+		c.logger.Debug("request", "url", url, "cache_hit", true, "duration", time.Since(start))
+		return data, nil
 	}
-	log.Printf("Cache miss for Evolution Chain URL: %s. Fetching from API.\\n", url)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return emptyResponse, fmt.Errorf("could not create request for evolution chain %s: %w", url, err)
+		return nil, fmt.Errorf("could not create request for %s: %w", url, err)
+	}
+
+	validatingCache, canRevalidate := c.cache.(ValidatingCache)
+	var staleBody []byte
+	if canRevalidate {
+		if body, validators, ok := validatingCache.GetStale(url); ok {
+			staleBody = body
+			if validators.ETag != "" {
+				req.Header.Set("If-None-Match", validators.ETag)
+			}
+			if validators.LastModified != "" {
+				req.Header.Set("If-Modified-Since", validators.LastModified)
+			}
+		}
 	}
 
 	res, err := c.httpClient.Do(req)
 	if err != nil {
-		return emptyResponse, fmt.Errorf("request to fetch evolution chain %s failed: %w", url, err)
+		c.logger.Warn("request failed", "url", url, "cache_hit", false, "duration", time.Since(start), "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrNetwork, err)
 	}
 	defer res.Body.Close()
 
-	if res.StatusCode == http.StatusNotFound {
-		return emptyResponse, fmt.Errorf("evolution chain at '%s' not found", url)
-	}
-	if res.StatusCode > 299 {
-		bodyBytes, _ := io.ReadAll(res.Body)
-		return emptyResponse, fmt.Errorf("API request for evolution chain %s failed with status %d: %s", url, res.StatusCode, string(bodyBytes))
+	if res.StatusCode == http.StatusNotModified && staleBody != nil {
+		validatingCache.Refresh(url)
+		c.logger.Debug("request", "url", url, "status", res.StatusCode, "cache_hit", true, "revalidated", true, "duration", time.Since(start))
+		return staleBody, nil
 	}
 
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return emptyResponse, fmt.Errorf("failed to read response body for evolution chain %s: %w", url, err)
+		c.logger.Warn("request failed", "url", url, "status", res.StatusCode, "cache_hit", false, "duration", time.Since(start), "error", err)
+		return nil, fmt.Errorf("%w: failed to read response from %s: %w", ErrNetwork, url, err)
 	}
-
-	var chainData EvolutionChainResponse
-	if err := json.Unmarshal(body, &chainData); err != nil {
-		return emptyResponse, fmt.Errorf("failed to unmarshal evolution chain data from %s: %w. Body: %s", url, err, string(body))
+	c.logger.Debug("request", "url", url, "status", res.StatusCode, "cache_hit", false, "conditional", staleBody != nil, "bytes", len(body), "duration", time.Since(start))
+	if res.StatusCode > 299 {
+		return nil, newHTTPError(res.StatusCode, url, body)
 	}
 
-	c.cache.Add(url, body)
-	return chainData, nil
+	validators := pokecache.Validators{
+		ETag:         res.Header.Get("ETag"),
+		LastModified: res.Header.Get("Last-Modified"),
+	}
+	if canRevalidate && !validators.IsZero() {
+		validatingCache.AddWithValidators(url, body, validators)
+	} else {
+		c.cache.Add(url, body)
+	}
+	return body, nil
 }
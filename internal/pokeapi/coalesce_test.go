@@ -0,0 +1,171 @@
+package pokeapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/pokecache"
+)
+
+// blockingServer holds every request until release is closed and counts how many
+// requests it received per path. Run with -race to check the client's coalescing.
+type blockingServer struct {
+	release chan struct{}
+	entered chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	hits    map[string]int
+	total   atomic.Int32
+}
+
+func newBlockingServer() *blockingServer {
+	return &blockingServer{
+		release: make(chan struct{}),
+		entered: make(chan struct{}),
+		hits:    make(map[string]int),
+	}
+}
+
+func (s *blockingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.total.Add(1)
+	s.mu.Lock()
+	s.hits[r.URL.Path]++
+	s.mu.Unlock()
+	s.once.Do(func() { close(s.entered) })
+
+	<-s.release
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/api/v2/pokemon/pikachu":
+		w.Write([]byte(`{"id":25,"name":"pikachu","base_experience":112,"stats":[{"base_stat":35,"stat":{"name":"hp"}}]}`))
+	case "/api/v2/pokemon/raichu":
+		w.Write([]byte(`{"id":26,"name":"raichu","base_experience":243}`))
+	case "/api/v2/region":
+		w.Write([]byte(`{"count":1,"next":null,"previous":null,"results":[{"name":"kanto","url":"https://pokeapi.co/api/v2/region/1/"}]}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestConcurrentFetchesAreCoalesced(t *testing.T) {
+	server := newBlockingServer()
+	client := pokeapi.NewClient(pokecache.NewCache(5*time.Minute), pokeapi.WithTransport(handlerTransport{server}))
+
+	const callers = 10
+	results := make([]pokeapi.PokemonData, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = client.FetchPokemon("pikachu")
+		}()
+	}
+
+	// Let the first request reach the server, give the other callers time to pile up
+	// behind it, then let it finish.
+	<-server.entered
+	time.Sleep(50 * time.Millisecond)
+	close(server.release)
+	wg.Wait()
+
+	if hits := server.hits["/api/v2/pokemon/pikachu"]; hits != 1 {
+		t.Errorf("expected 1 HTTP request for %d concurrent callers, got %d", callers, hits)
+	}
+	for i := range callers {
+		if errs[i] != nil {
+			t.Fatalf("caller %d got error: %v", i, errs[i])
+		}
+		if !reflect.DeepEqual(results[i], results[0]) {
+			t.Errorf("caller %d got %+v; want the shared result %+v", i, results[i], results[0])
+		}
+	}
+	if results[0].Name != "pikachu" {
+		t.Errorf("unexpected shared result: %+v", results[0])
+	}
+}
+
+func TestConcurrentFetchesOfDifferentURLsAreNotCoalesced(t *testing.T) {
+	server := newBlockingServer()
+	close(server.release)
+	client := pokeapi.NewClient(pokecache.NewCache(5*time.Minute), pokeapi.WithTransport(handlerTransport{server}))
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"pikachu", "raichu", "missingno"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.FetchPokemon(name)
+		}()
+	}
+	wg.Wait()
+
+	if total := server.total.Load(); total != 3 {
+		t.Errorf("expected one request per distinct URL (3), got %d", total)
+	}
+}
+
+func TestCoalescedErrorsAreNotCached(t *testing.T) {
+	server := newBlockingServer()
+	close(server.release)
+	client := pokeapi.NewClient(pokecache.NewCache(5*time.Minute), pokeapi.WithTransport(handlerTransport{server}))
+
+	for range 2 {
+		if _, err := client.FetchPokemon("missingno"); err == nil {
+			t.Fatalf("expected an error for an unknown Pokemon")
+		}
+	}
+	if hits := server.hits["/api/v2/pokemon/missingno"]; hits != 2 {
+		t.Errorf("a failed lookup should be retried on the next call, got %d requests; want 2", hits)
+	}
+}
+
+func TestCancellingOneCallerLeavesTheSharedRequestRunning(t *testing.T) {
+	server := newBlockingServer()
+	client := pokeapi.NewClient(pokecache.NewCache(5*time.Minute), pokeapi.WithTransport(handlerTransport{server}))
+	url := pokeapi.ListURL("region", 0, pokeapi.DefaultPageLimit)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := client.FetchResourceList(ctx, url)
+		firstErr <- err
+	}()
+	<-server.entered
+
+	type result struct {
+		page pokeapi.NamedAPIResourceList
+		err  error
+	}
+	second := make(chan result, 1)
+	go func() {
+		page, err := client.FetchResourceList(context.Background(), url)
+		second <- result{page, err}
+	}()
+	// Give the second caller time to join the first one's request, then give up on it.
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("the cancelled caller got %v; want context.Canceled", err)
+	}
+
+	close(server.release)
+	res := <-second
+	if res.err != nil {
+		t.Fatalf("the caller that kept waiting got error: %v", res.err)
+	}
+	if len(res.page.Results) != 1 || res.page.Results[0].Name != "kanto" {
+		t.Errorf("unexpected page: %+v", res.page)
+	}
+	if hits := server.hits["/api/v2/region"]; hits != 1 {
+		t.Errorf("expected the callers to share 1 HTTP request, got %d", hits)
+	}
+}
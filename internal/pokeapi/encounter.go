@@ -0,0 +1,112 @@
+package pokeapi
+
+import "slices"
+
+// PokemonEncounter is one Pokemon that can be met in a location area, with how it
+// can be met in each game version.
+type PokemonEncounter struct {
+	Pokemon        Pokemon                  `json:"pokemon"`
+	VersionDetails []VersionEncounterDetail `json:"version_details"`
+}
+
+// VersionEncounterDetail lists the ways a Pokemon can be encountered in one game version.
+type VersionEncounterDetail struct {
+	Version          NamedAPIResource `json:"version"`
+	MaxChance        int              `json:"max_chance"`
+	EncounterDetails []Encounter      `json:"encounter_details"`
+}
+
+// Encounter is a single way of meeting a Pokemon in an area: with a method such as
+// "walk", "surf" or "old-rod", at a level in a range, with a percent chance, and
+// possibly only under conditions such as "time-night" or "swarm-yes".
+type Encounter struct {
+	MinLevel        int                `json:"min_level"`
+	MaxLevel        int                `json:"max_level"`
+	Chance          int                `json:"chance"`
+	Method          NamedAPIResource   `json:"method"`
+	ConditionValues []NamedAPIResource `json:"condition_values"`
+}
+
+// EncounterSlot is one entry of an area's encounter table.
+type EncounterSlot struct {
+	Pokemon  string
+	Chance   int
+	MinLevel int
+	MaxLevel int
+}
+
+// Methods returns the encounter methods of a Pokemon in the area, in the order
+// PokeAPI lists them, without duplicates.
+func (e PokemonEncounter) Methods() []string {
+	var methods []string
+	for _, version := range e.VersionDetails {
+		for _, detail := range version.EncounterDetails {
+			if !slices.Contains(methods, detail.Method.Name) {
+				methods = append(methods, detail.Method.Name)
+			}
+		}
+	}
+	return methods
+}
+
+// EncounterTable returns the slots of the area's encounter table for method, keeping only
+// the encounters for which available returns true (e.g. to check conditions). Game
+// versions have tables of their own, with version exclusives and different chances, so
+// every slot comes from one version: see EncounterVersion.
+func (a LocationAreaDetail) EncounterTable(method string, available func(Encounter) bool) []EncounterSlot {
+	version := a.EncounterVersion(method, available)
+	var table []EncounterSlot
+	for _, pokemon := range a.PokemonEncounters {
+		for _, details := range pokemon.VersionDetails {
+			if details.Version.Name != version {
+				continue
+			}
+			for _, detail := range details.EncounterDetails {
+				if detail.Method.Name != method || detail.Chance <= 0 || !available(detail) {
+					continue
+				}
+				table = append(table, EncounterSlot{
+					Pokemon:  pokemon.Pokemon.Name,
+					Chance:   detail.Chance,
+					MinLevel: detail.MinLevel,
+					MaxLevel: detail.MaxLevel,
+				})
+			}
+		}
+	}
+	return table
+}
+
+// EncounterVersion returns the game version whose table EncounterTable uses for method:
+// the first one PokeAPI lists with an encounter by that method for which available
+// returns true, or "" if none has any. A version whose encounters are all unavailable
+// right now (e.g. only at night) is passed over for one that has some.
+func (a LocationAreaDetail) EncounterVersion(method string, available func(Encounter) bool) string {
+	for _, pokemon := range a.PokemonEncounters {
+		for _, details := range pokemon.VersionDetails {
+			for _, detail := range details.EncounterDetails {
+				if detail.Method.Name == method && detail.Chance > 0 && available(detail) {
+					return details.Version.Name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// LevelRange returns the lowest and highest level the Pokemon is met at in the area,
+// across all versions. ok is false if the area has no level data for it.
+func (e PokemonEncounter) LevelRange() (minLevel, maxLevel int, ok bool) {
+	for _, version := range e.VersionDetails {
+		for _, detail := range version.EncounterDetails {
+			if !ok || detail.MinLevel < minLevel {
+				minLevel = detail.MinLevel
+			}
+			if !ok || detail.MaxLevel > maxLevel {
+				maxLevel = detail.MaxLevel
+			}
+			ok = true
+		}
+	}
+	return minLevel, maxLevel, ok
+}
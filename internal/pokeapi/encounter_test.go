@@ -0,0 +1,118 @@
+package pokeapi_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+)
+
+func encounter(pokemon string, versions ...pokeapi.VersionEncounterDetail) pokeapi.PokemonEncounter {
+	return pokeapi.PokemonEncounter{Pokemon: pokeapi.Pokemon{Name: pokemon}, VersionDetails: versions}
+}
+
+func inVersion(version string, details ...pokeapi.Encounter) pokeapi.VersionEncounterDetail {
+	return pokeapi.VersionEncounterDetail{Version: pokeapi.NamedAPIResource{Name: version}, EncounterDetails: details}
+}
+
+func by(method string, chance, minLevel, maxLevel int) pokeapi.Encounter {
+	return pokeapi.Encounter{Method: pokeapi.NamedAPIResource{Name: method}, Chance: chance, MinLevel: minLevel, MaxLevel: maxLevel}
+}
+
+// canalave is laid out like Canalave City's area: sea and fishing encounters only.
+var canalave = pokeapi.LocationAreaDetail{
+	Name: "canalave-city-area",
+	PokemonEncounters: []pokeapi.PokemonEncounter{
+		encounter("tentacool", inVersion("diamond", by("surf", 60, 20, 30))),
+		encounter("wingull", inVersion("diamond", by("surf", 30, 20, 30))),
+		encounter("tentacruel", inVersion("diamond", by("surf", 5, 20, 40))),
+		encounter("magikarp", inVersion("diamond", by("old-rod", 100, 3, 10), by("good-rod", 30, 10, 25))),
+		encounter("staryu", inVersion("diamond", by("good-rod", 40, 15, 25))),
+	},
+}
+
+func TestEncounterLevelRangeAndMethods(t *testing.T) {
+	magikarp := canalave.PokemonEncounters[3]
+	if minLevel, maxLevel, ok := magikarp.LevelRange(); !ok || minLevel != 3 || maxLevel != 25 {
+		t.Errorf("%s LevelRange() = %d, %d, %v; want 3, 25, true", magikarp.Pokemon.Name, minLevel, maxLevel, ok)
+	}
+	if _, _, ok := (pokeapi.PokemonEncounter{}).LevelRange(); ok {
+		t.Errorf("expected no level range without encounter details")
+	}
+	if methods := magikarp.Methods(); fmt.Sprint(methods) != "[old-rod good-rod]" {
+		t.Errorf("magikarp methods = %v; want [old-rod good-rod]", methods)
+	}
+}
+
+func TestEncounterTable(t *testing.T) {
+	all := func(pokeapi.Encounter) bool { return true }
+	tests := []struct {
+		method    string
+		available func(pokeapi.Encounter) bool
+		want      string
+	}{
+		{"surf", all, "[{tentacool 60 20 30} {wingull 30 20 30} {tentacruel 5 20 40}]"},
+		{"good-rod", all, "[{magikarp 30 10 25} {staryu 40 15 25}]"},
+		{"walk", all, "[]"},
+		{"surf", func(pokeapi.Encounter) bool { return false }, "[]"},
+		{"surf", func(e pokeapi.Encounter) bool { return e.MaxLevel > 30 }, "[{tentacruel 5 20 40}]"},
+	}
+
+	for _, tc := range tests {
+		if got := fmt.Sprint(canalave.EncounterTable(tc.method, tc.available)); got != tc.want {
+			t.Errorf("EncounterTable(%s) = %s; want %s", tc.method, got, tc.want)
+		}
+	}
+}
+
+func TestEncounterTableKeepsToOneVersion(t *testing.T) {
+	// Diamond and Pearl have tables of their own: kricketot is a Pearl exclusive, and
+	// Pearl's chances add up differently.
+	route201 := pokeapi.LocationAreaDetail{
+		Name: "sinnoh-route-201-area",
+		PokemonEncounters: []pokeapi.PokemonEncounter{
+			encounter("starly", inVersion("diamond", by("walk", 50, 2, 3)), inVersion("pearl", by("walk", 40, 2, 3))),
+			encounter("bidoof", inVersion("diamond", by("walk", 50, 2, 3)), inVersion("pearl", by("walk", 40, 2, 3))),
+			encounter("kricketot", inVersion("pearl", by("walk", 20, 2, 3))),
+		},
+	}
+
+	all := func(pokeapi.Encounter) bool { return true }
+	if version := route201.EncounterVersion("walk", all); version != "diamond" {
+		t.Errorf("EncounterVersion(walk) = %q; want diamond, the first version listed", version)
+	}
+	walk := route201.EncounterTable("walk", all)
+	if got := fmt.Sprint(walk); got != "[{starly 50 2 3} {bidoof 50 2 3}]" {
+		t.Errorf("walk table = %s; want Diamond's starly and bidoof only", got)
+	}
+	if version := route201.EncounterVersion("surf", all); version != "" {
+		t.Errorf("EncounterVersion(surf) = %q; want none", version)
+	}
+}
+
+func TestEncounterVersionSkipsVersionsWithNothingAvailable(t *testing.T) {
+	// Here Diamond only has hoothoot, at night, while Pearl has starly all day.
+	hoothoot := by("walk", 100, 5, 6)
+	hoothoot.ConditionValues = []pokeapi.NamedAPIResource{{Name: "time-night"}}
+	area := pokeapi.LocationAreaDetail{
+		Name: "night-area",
+		PokemonEncounters: []pokeapi.PokemonEncounter{
+			encounter("hoothoot", inVersion("diamond", hoothoot)),
+			encounter("starly", inVersion("pearl", by("walk", 100, 4, 5))),
+		},
+	}
+	day := func(e pokeapi.Encounter) bool { return len(e.ConditionValues) == 0 }
+
+	if version := area.EncounterVersion("walk", day); version != "pearl" {
+		t.Errorf("EncounterVersion(walk) by day = %q; want pearl, the first with something to meet", version)
+	}
+	if got := fmt.Sprint(area.EncounterTable("walk", day)); got != "[{starly 100 4 5}]" {
+		t.Errorf("walk table by day = %s; want Pearl's starly", got)
+	}
+	if got := fmt.Sprint(area.EncounterTable("walk", func(pokeapi.Encounter) bool { return true })); got != "[{hoothoot 100 5 6}]" {
+		t.Errorf("walk table at night = %s; want Diamond's hoothoot", got)
+	}
+	if version := area.EncounterVersion("walk", func(pokeapi.Encounter) bool { return false }); version != "" {
+		t.Errorf("EncounterVersion(walk) with nothing available = %q; want none", version)
+	}
+}
@@ -0,0 +1,78 @@
+package pokeapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrNotFound matches errors for resources PokeAPI doesn't know (HTTP 404).
+	ErrNotFound = errors.New("pokeapi: resource not found")
+	// ErrRateLimited matches errors for requests PokeAPI refused with HTTP 429.
+	ErrRateLimited = errors.New("pokeapi: rate limited")
+	// ErrNetwork matches errors where PokeAPI couldn't be reached at all.
+	ErrNetwork = errors.New("pokeapi: network error")
+)
+
+// maxErrorBodyLen caps how much of an error response body is kept in an HTTPError.
+const maxErrorBodyLen = 200
+
+// HTTPError is returned when PokeAPI answers with a non-2xx status.
+// Use errors.Is with ErrNotFound or ErrRateLimited to check for the common cases.
+type HTTPError struct {
+	Status int
+	URL    string
+	Body   string
+}
+
+func (e *HTTPError) Error() string {
+	msg := fmt.Sprintf("%s returned %d %s", e.URL, e.Status, http.StatusText(e.Status))
+	if e.Body != "" && e.Status != http.StatusNotFound {
+		msg += ": " + e.Body
+	}
+	return msg
+}
+
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Status == http.StatusNotFound
+	case ErrRateLimited:
+		return e.Status == http.StatusTooManyRequests
+	}
+	return false
+}
+
+func newHTTPError(status int, url string, body []byte) *HTTPError {
+	b := string(body)
+	if len(b) > maxErrorBodyLen {
+		b = b[:maxErrorBodyLen] + "..."
+	}
+	return &HTTPError{Status: status, URL: url, Body: b}
+}
+
+// DecodeError is returned when a PokeAPI response body can't be decoded.
+type DecodeError struct {
+	URL string
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode response from %s: %v", e.URL, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// decode unmarshals body into a T, reporting failures as a *DecodeError.
+func decode[T any](url string, body []byte) (T, error) {
+	var v T
+	if err := json.Unmarshal(body, &v); err != nil {
+		var zero T
+		return zero, &DecodeError{URL: url, Err: err}
+	}
+	return v, nil
+}
@@ -0,0 +1,87 @@
+package pokeapi_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/pokecache"
+)
+
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("dial tcp: connection refused")
+}
+
+func TestFetchErrorsAreTyped(t *testing.T) {
+	respond := func(status int, body string) http.RoundTripper {
+		return handlerTransport{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		})}
+	}
+
+	tests := []struct {
+		name          string
+		transport     http.RoundTripper
+		wantNotFound  bool
+		wantRateLimit bool
+		wantNetwork   bool
+		wantStatus    int // 0 when no *HTTPError is expected
+		wantDecode    bool
+	}{
+		{name: "not found", transport: respond(http.StatusNotFound, "Not Found"), wantNotFound: true, wantStatus: http.StatusNotFound},
+		{name: "rate limited", transport: respond(http.StatusTooManyRequests, "slow down"), wantRateLimit: true, wantStatus: http.StatusTooManyRequests},
+		{name: "server error", transport: respond(http.StatusBadGateway, "upstream down"), wantStatus: http.StatusBadGateway},
+		{name: "malformed body", transport: respond(http.StatusOK, `{"name": 42`), wantDecode: true},
+		{name: "network failure", transport: failingTransport{}, wantNetwork: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := pokeapi.NewClient(pokecache.NewCache(5*time.Minute), pokeapi.WithTransport(tc.transport))
+			_, err := client.FetchPokemon("pikachu")
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+
+			if got := errors.Is(err, pokeapi.ErrNotFound); got != tc.wantNotFound {
+				t.Errorf("errors.Is(err, ErrNotFound) = %v; want %v (err: %v)", got, tc.wantNotFound, err)
+			}
+			if got := errors.Is(err, pokeapi.ErrRateLimited); got != tc.wantRateLimit {
+				t.Errorf("errors.Is(err, ErrRateLimited) = %v; want %v (err: %v)", got, tc.wantRateLimit, err)
+			}
+			if got := errors.Is(err, pokeapi.ErrNetwork); got != tc.wantNetwork {
+				t.Errorf("errors.Is(err, ErrNetwork) = %v; want %v (err: %v)", got, tc.wantNetwork, err)
+			}
+
+			var httpErr *pokeapi.HTTPError
+			if errors.As(err, &httpErr) {
+				if httpErr.Status != tc.wantStatus {
+					t.Errorf("HTTPError.Status = %d; want %d", httpErr.Status, tc.wantStatus)
+				}
+				if httpErr.URL != pokeapi.BaseURL+"/pokemon/pikachu" {
+					t.Errorf("HTTPError.URL = %s", httpErr.URL)
+				}
+			} else if tc.wantStatus != 0 {
+				t.Errorf("expected an *HTTPError with status %d, got %v", tc.wantStatus, err)
+			}
+
+			var decodeErr *pokeapi.DecodeError
+			if got := errors.As(err, &decodeErr); got != tc.wantDecode {
+				t.Errorf("errors.As(err, *DecodeError) = %v; want %v (err: %v)", got, tc.wantDecode, err)
+			}
+		})
+	}
+}
+
+func TestHTTPErrorMessage(t *testing.T) {
+	err := &pokeapi.HTTPError{Status: http.StatusServiceUnavailable, URL: "https://pokeapi.co/api/v2/pokemon/ditto", Body: "maintenance"}
+	want := "https://pokeapi.co/api/v2/pokemon/ditto returned 503 Service Unavailable: maintenance"
+	if err.Error() != want {
+		t.Errorf("Error() = %q; want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,236 @@
+package pokeapi
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Genders as PokeAPI numbers them in evolution details.
+const (
+	GenderFemale = 1
+	GenderMale   = 2
+)
+
+// EvolutionDetail is one way a species evolves into the next: a trigger ("level-up",
+// "use-item", "trade", ...) plus every condition that must hold when it fires. Unset
+// conditions are null (or false/"") in PokeAPI's data. A species can have several
+// details, e.g. Leafeon by leveling up near a moss rock or by using a Leaf Stone.
+type EvolutionDetail struct {
+	Trigger  NamedAPIResource  `json:"trigger"`
+	MinLevel *int              `json:"min_level"`
+	Item     *NamedAPIResource `json:"item"`      // Item used on the Pokemon, for "use-item"
+	HeldItem *NamedAPIResource `json:"held_item"` // Item the Pokemon holds, e.g. metal-coat when traded
+	Gender   *int              `json:"gender"`    // GenderFemale or GenderMale
+
+	KnownMove     *NamedAPIResource `json:"known_move"`
+	KnownMoveType *NamedAPIResource `json:"known_move_type"`
+	Location      *NamedAPIResource `json:"location"`
+
+	MinHappiness *int `json:"min_happiness"`
+	MinBeauty    *int `json:"min_beauty"`
+	MinAffection *int `json:"min_affection"`
+
+	NeedsOverworldRain bool              `json:"needs_overworld_rain"`
+	PartySpecies       *NamedAPIResource `json:"party_species"` // Species that must be in the party
+	PartyType          *NamedAPIResource `json:"party_type"`    // Type of another party member
+
+	// RelativePhysicalStats compares Attack to Defense: 1 for Attack > Defense,
+	// 0 for equal and -1 for Attack < Defense, e.g. for Tyrogue's three evolutions.
+	RelativePhysicalStats *int `json:"relative_physical_stats"`
+
+	TimeOfDay      string            `json:"time_of_day"` // "day", "night" or "" for any time, e.g. Espeon by day and Umbreon by night
+	TradeSpecies   *NamedAPIResource `json:"trade_species"`
+	TurnUpsideDown bool              `json:"turn_upside_down"`
+}
+
+// EvolutionContext is everything evolution details are checked against: the Pokemon
+// itself, what just happened to it, and the world around it.
+type EvolutionContext struct {
+	Trigger string // What happened: "level-up", "use-item", "trade", ...
+	Item    string // Item used on the Pokemon, for "use-item"
+
+	Level     int
+	Happiness int
+	Beauty    int
+	Affection int
+	Gender    int // GenderFemale, GenderMale or 0 for genderless
+	Attack    int
+	Defense   int
+	HeldItem  string
+
+	KnownMoves     []string
+	KnownMoveTypes []string
+
+	Location      string   // Location the player is at
+	TimeOfDay     string   // "day" or "night"
+	OverworldRain bool     // Whether it is raining where the player is
+	UpsideDown    bool     // Whether the player holds the game upside down
+	PartySpecies  []string // Species of the other party members
+	PartyTypes    []string // Types of the other party members
+	TradeSpecies  string   // Species the Pokemon is traded for, for "trade"
+}
+
+// Met reports whether the evolution described by d happens in ctx: the trigger
+// matches and every condition d sets holds.
+func (d EvolutionDetail) Met(ctx EvolutionContext) bool {
+	if d.Trigger.Name != ctx.Trigger {
+		return false
+	}
+	switch {
+	case d.MinLevel != nil && ctx.Level < *d.MinLevel,
+		d.Item != nil && d.Item.Name != ctx.Item,
+		d.HeldItem != nil && d.HeldItem.Name != ctx.HeldItem,
+		d.Gender != nil && *d.Gender != ctx.Gender,
+		d.KnownMove != nil && !slices.Contains(ctx.KnownMoves, d.KnownMove.Name),
+		d.KnownMoveType != nil && !slices.Contains(ctx.KnownMoveTypes, d.KnownMoveType.Name),
+		d.Location != nil && d.Location.Name != ctx.Location,
+		d.MinHappiness != nil && ctx.Happiness < *d.MinHappiness,
+		d.MinBeauty != nil && ctx.Beauty < *d.MinBeauty,
+		d.MinAffection != nil && ctx.Affection < *d.MinAffection,
+		d.NeedsOverworldRain && !ctx.OverworldRain,
+		d.PartySpecies != nil && !slices.Contains(ctx.PartySpecies, d.PartySpecies.Name),
+		d.PartyType != nil && !slices.Contains(ctx.PartyTypes, d.PartyType.Name),
+		d.RelativePhysicalStats != nil && *d.RelativePhysicalStats != compare(ctx.Attack, ctx.Defense),
+		d.TimeOfDay != "" && d.TimeOfDay != ctx.TimeOfDay,
+		d.TradeSpecies != nil && d.TradeSpecies.Name != ctx.TradeSpecies,
+		d.TurnUpsideDown && !ctx.UpsideDown:
+		return false
+	}
+	return true
+}
+
+// String describes d for players, e.g. "Lv 16", "use fire-stone", "trade holding
+// metal-coat" or "level up, happiness 160+, day".
+func (d EvolutionDetail) String() string {
+	var parts []string
+	switch d.Trigger.Name {
+	case "level-up":
+		if d.MinLevel != nil {
+			parts = append(parts, fmt.Sprintf("Lv %d", *d.MinLevel))
+		} else {
+			parts = append(parts, "level up")
+		}
+	case "use-item":
+		if d.Item != nil {
+			parts = append(parts, "use "+d.Item.Name)
+		} else {
+			parts = append(parts, "use an item")
+		}
+	case "trade":
+		trade := "trade"
+		if d.HeldItem != nil {
+			trade += " holding " + d.HeldItem.Name
+		}
+		if d.TradeSpecies != nil {
+			trade += " for " + d.TradeSpecies.Name
+		}
+		parts = append(parts, trade)
+	default:
+		parts = append(parts, strings.ReplaceAll(d.Trigger.Name, "-", " "))
+		if d.MinLevel != nil {
+			parts = append(parts, fmt.Sprintf("Lv %d", *d.MinLevel))
+		}
+	}
+
+	if d.HeldItem != nil && d.Trigger.Name != "trade" {
+		parts = append(parts, "holding "+d.HeldItem.Name)
+	}
+	if d.Gender != nil {
+		switch *d.Gender {
+		case GenderFemale:
+			parts = append(parts, "female")
+		case GenderMale:
+			parts = append(parts, "male")
+		}
+	}
+	if d.KnownMove != nil {
+		parts = append(parts, "knowing "+d.KnownMove.Name)
+	}
+	if d.KnownMoveType != nil {
+		parts = append(parts, "knowing a "+d.KnownMoveType.Name+" move")
+	}
+	if d.Location != nil {
+		parts = append(parts, "at "+d.Location.Name)
+	}
+	if d.MinHappiness != nil {
+		parts = append(parts, fmt.Sprintf("happiness %d+", *d.MinHappiness))
+	}
+	if d.MinBeauty != nil {
+		parts = append(parts, fmt.Sprintf("beauty %d+", *d.MinBeauty))
+	}
+	if d.MinAffection != nil {
+		parts = append(parts, fmt.Sprintf("affection %d+", *d.MinAffection))
+	}
+	if d.NeedsOverworldRain {
+		parts = append(parts, "in the rain")
+	}
+	if d.PartySpecies != nil {
+		parts = append(parts, "with "+d.PartySpecies.Name+" in the party")
+	}
+	if d.PartyType != nil {
+		parts = append(parts, "with a "+d.PartyType.Name+" type in the party")
+	}
+	if d.RelativePhysicalStats != nil {
+		parts = append(parts, [...]string{"Attack < Defense", "Attack = Defense", "Attack > Defense"}[compare(*d.RelativePhysicalStats, 0)+1])
+	}
+	if d.TimeOfDay != "" {
+		parts = append(parts, d.TimeOfDay)
+	}
+	if d.TurnUpsideDown {
+		parts = append(parts, "upside down")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// NeedsMoves reports whether checking d requires the moves the Pokemon knows.
+func (d EvolutionDetail) NeedsMoves() bool {
+	return d.KnownMove != nil || d.KnownMoveType != nil
+}
+
+func compare(a, b int) int {
+	switch {
+	case a > b:
+		return 1
+	case a < b:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// Find returns the link of species in the chain starting at l.
+func (l CorrectedChainLink) Find(species string) (CorrectedChainLink, bool) {
+	if l.Species.Name == species {
+		return l, true
+	}
+	for _, next := range l.EvolvesTo {
+		if found, ok := next.Find(species); ok {
+			return found, true
+		}
+	}
+	return CorrectedChainLink{}, false
+}
+
+// NextEvolution returns the first of l's evolutions that happens in ctx, along with
+// the evolution detail that allowed it. Branches are tried in PokeAPI's order.
+func (l CorrectedChainLink) NextEvolution(ctx EvolutionContext) (CorrectedChainLink, EvolutionDetail, bool) {
+	for _, evolution := range l.EvolvesTo {
+		for _, detail := range evolution.EvolutionDetails {
+			if detail.Met(ctx) {
+				return evolution, detail, true
+			}
+		}
+	}
+	return CorrectedChainLink{}, EvolutionDetail{}, false
+}
+
+// NeedsMoves reports whether checking any of l's evolutions requires known moves.
+func (l CorrectedChainLink) NeedsMoves() bool {
+	for _, evolution := range l.EvolvesTo {
+		if slices.ContainsFunc(evolution.EvolutionDetails, EvolutionDetail.NeedsMoves) {
+			return true
+		}
+	}
+	return false
+}
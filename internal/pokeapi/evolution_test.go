@@ -0,0 +1,203 @@
+package pokeapi_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/pokeapi/pokeapitest"
+)
+
+func intPtr(i int) *int { return &i }
+
+func ref(name string) *pokeapi.NamedAPIResource {
+	return &pokeapi.NamedAPIResource{Name: name}
+}
+
+func TestEvolutionDetailMet(t *testing.T) {
+	levelUp := func(d pokeapi.EvolutionDetail) pokeapi.EvolutionDetail {
+		d.Trigger.Name = "level-up"
+		return d
+	}
+	base := pokeapi.EvolutionContext{Trigger: "level-up", Level: 30, Happiness: 70, Gender: pokeapi.GenderMale, Attack: 50, Defense: 40, TimeOfDay: "day"}
+
+	tests := []struct {
+		name   string
+		detail pokeapi.EvolutionDetail
+		ctx    func(*pokeapi.EvolutionContext)
+		want   bool
+	}{
+		{"plain level-up", levelUp(pokeapi.EvolutionDetail{}), nil, true},
+		{"other trigger", levelUp(pokeapi.EvolutionDetail{}), func(c *pokeapi.EvolutionContext) { c.Trigger = "trade" }, false},
+		{"min level reached", levelUp(pokeapi.EvolutionDetail{MinLevel: intPtr(30)}), nil, true},
+		{"min level not reached", levelUp(pokeapi.EvolutionDetail{MinLevel: intPtr(31)}), nil, false},
+		{"min happiness", levelUp(pokeapi.EvolutionDetail{MinHappiness: intPtr(160)}), nil, false},
+		{"min happiness reached", levelUp(pokeapi.EvolutionDetail{MinHappiness: intPtr(160)}), func(c *pokeapi.EvolutionContext) { c.Happiness = 160 }, true},
+		{"min beauty", levelUp(pokeapi.EvolutionDetail{MinBeauty: intPtr(171)}), nil, false},
+		{"min affection", levelUp(pokeapi.EvolutionDetail{MinAffection: intPtr(2)}), nil, false},
+		{"gender", levelUp(pokeapi.EvolutionDetail{Gender: intPtr(pokeapi.GenderFemale)}), nil, false},
+		{"gender matches", levelUp(pokeapi.EvolutionDetail{Gender: intPtr(pokeapi.GenderMale)}), nil, true},
+		{"held item", levelUp(pokeapi.EvolutionDetail{HeldItem: ref("razor-fang")}), nil, false},
+		{"held item held", levelUp(pokeapi.EvolutionDetail{HeldItem: ref("razor-fang")}), func(c *pokeapi.EvolutionContext) { c.HeldItem = "razor-fang" }, true},
+		{"known move", levelUp(pokeapi.EvolutionDetail{KnownMove: ref("ancient-power")}), func(c *pokeapi.EvolutionContext) { c.KnownMoves = []string{"tackle", "ancient-power"} }, true},
+		{"unknown move", levelUp(pokeapi.EvolutionDetail{KnownMove: ref("ancient-power")}), func(c *pokeapi.EvolutionContext) { c.KnownMoves = []string{"tackle"} }, false},
+		{"known move type", levelUp(pokeapi.EvolutionDetail{KnownMoveType: ref("fairy")}), func(c *pokeapi.EvolutionContext) { c.KnownMoveTypes = []string{"normal", "fairy"} }, true},
+		{"location", levelUp(pokeapi.EvolutionDetail{Location: ref("eterna-forest")}), func(c *pokeapi.EvolutionContext) { c.Location = "eterna-city" }, false},
+		{"location matches", levelUp(pokeapi.EvolutionDetail{Location: ref("eterna-forest")}), func(c *pokeapi.EvolutionContext) { c.Location = "eterna-forest" }, true},
+		{"needs rain", levelUp(pokeapi.EvolutionDetail{NeedsOverworldRain: true}), nil, false},
+		{"party species", levelUp(pokeapi.EvolutionDetail{PartySpecies: ref("remoraid")}), func(c *pokeapi.EvolutionContext) { c.PartySpecies = []string{"remoraid"} }, true},
+		{"party type", levelUp(pokeapi.EvolutionDetail{PartyType: ref("dark")}), func(c *pokeapi.EvolutionContext) { c.PartyTypes = []string{"fire"} }, false},
+		{"attack above defense", levelUp(pokeapi.EvolutionDetail{RelativePhysicalStats: intPtr(1)}), nil, true},
+		{"attack equals defense", levelUp(pokeapi.EvolutionDetail{RelativePhysicalStats: intPtr(0)}), nil, false},
+		{"time of day", levelUp(pokeapi.EvolutionDetail{TimeOfDay: "night"}), nil, false},
+		{"upside down", levelUp(pokeapi.EvolutionDetail{TurnUpsideDown: true}), nil, false},
+		{"use-item", pokeapi.EvolutionDetail{Trigger: pokeapi.NamedAPIResource{Name: "use-item"}, Item: ref("fire-stone")}, func(c *pokeapi.EvolutionContext) { c.Trigger, c.Item = "use-item", "fire-stone" }, true},
+		{"use another item", pokeapi.EvolutionDetail{Trigger: pokeapi.NamedAPIResource{Name: "use-item"}, Item: ref("fire-stone")}, func(c *pokeapi.EvolutionContext) { c.Trigger, c.Item = "use-item", "water-stone" }, false},
+		{"trade for species", pokeapi.EvolutionDetail{Trigger: pokeapi.NamedAPIResource{Name: "trade"}, TradeSpecies: ref("shelmet")}, func(c *pokeapi.EvolutionContext) { c.Trigger, c.TradeSpecies = "trade", "shelmet" }, true},
+	}
+	for _, tc := range tests {
+		ctx := base
+		if tc.ctx != nil {
+			tc.ctx(&ctx)
+		}
+		if got := tc.detail.Met(ctx); got != tc.want {
+			t.Errorf("%s: Met = %v; want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestNextEvolutionResolvesBranches(t *testing.T) {
+	physical := func(relative int) []pokeapi.EvolutionDetail {
+		details := pokeapitest.LevelUp(20)
+		details[0].RelativePhysicalStats = &relative
+		return details
+	}
+	tyrogue := pokeapitest.Link("tyrogue", nil,
+		pokeapitest.Link("hitmonlee", physical(1)),
+		pokeapitest.Link("hitmonchan", physical(-1)),
+		pokeapitest.Link("hitmontop", physical(0)),
+	)
+
+	for _, tc := range []struct {
+		attack, defense, level int
+		want                   string
+	}{
+		{60, 35, 20, "hitmonlee"},
+		{35, 60, 20, "hitmonchan"},
+		{35, 35, 20, "hitmontop"},
+		{60, 35, 19, ""},
+	} {
+		ctx := pokeapi.EvolutionContext{Trigger: "level-up", Level: tc.level, Attack: tc.attack, Defense: tc.defense}
+		evolution, _, ok := tyrogue.NextEvolution(ctx)
+		if got := evolution.Species.Name; got != tc.want || ok != (tc.want != "") {
+			t.Errorf("tyrogue with attack %d, defense %d at level %d evolves into %q; want %q", tc.attack, tc.defense, tc.level, got, tc.want)
+		}
+	}
+}
+
+func TestEvolutionDetailString(t *testing.T) {
+	detail := func(trigger string, d pokeapi.EvolutionDetail) pokeapi.EvolutionDetail {
+		d.Trigger.Name = trigger
+		return d
+	}
+	tests := []struct {
+		detail pokeapi.EvolutionDetail
+		want   string
+	}{
+		{detail("level-up", pokeapi.EvolutionDetail{MinLevel: intPtr(16)}), "Lv 16"},
+		{detail("use-item", pokeapi.EvolutionDetail{Item: ref("fire-stone")}), "use fire-stone"},
+		{detail("trade", pokeapi.EvolutionDetail{HeldItem: ref("metal-coat")}), "trade holding metal-coat"},
+		{detail("trade", pokeapi.EvolutionDetail{TradeSpecies: ref("shelmet")}), "trade for shelmet"},
+		{detail("level-up", pokeapi.EvolutionDetail{MinHappiness: intPtr(160), TimeOfDay: "day"}), "level up, happiness 160+, day"},
+		{detail("level-up", pokeapi.EvolutionDetail{MinLevel: intPtr(20), RelativePhysicalStats: intPtr(-1)}), "Lv 20, Attack < Defense"},
+		{detail("level-up", pokeapi.EvolutionDetail{HeldItem: ref("razor-claw"), TimeOfDay: "night"}), "level up, holding razor-claw, night"},
+		{detail("level-up", pokeapi.EvolutionDetail{MinLevel: intPtr(20), Gender: intPtr(pokeapi.GenderFemale)}), "Lv 20, female"},
+		{detail("level-up", pokeapi.EvolutionDetail{KnownMoveType: ref("fairy"), MinAffection: intPtr(2)}), "level up, knowing a fairy move, affection 2+"},
+		{detail("level-up", pokeapi.EvolutionDetail{PartySpecies: ref("remoraid")}), "level up, with remoraid in the party"},
+		{detail("shed", pokeapi.EvolutionDetail{}), "shed"},
+		{detail("three-critical-hits", pokeapi.EvolutionDetail{}), "three critical hits"},
+	}
+
+	for _, tc := range tests {
+		if got := tc.detail.String(); got != tc.want {
+			t.Errorf("String() = %q; want %q", got, tc.want)
+		}
+	}
+}
+
+func TestEvolutionChainDetails(t *testing.T) {
+	chain := pokeapi.EvolutionChainResponse{
+		ID: 10,
+		Chain: pokeapitest.Link("pichu", nil,
+			pokeapitest.Link("pikachu", []pokeapi.EvolutionDetail{{MinHappiness: intPtr(220), Trigger: pokeapi.NamedAPIResource{Name: "level-up"}}},
+				pokeapitest.Link("raichu", pokeapitest.UseItem("thunder-stone")),
+			),
+		),
+	}
+
+	pichu, ok := chain.Chain.Find("pichu")
+	if !ok {
+		t.Fatalf("pichu not found in its own chain")
+	}
+	ctx := pokeapi.EvolutionContext{Trigger: "level-up", Level: 50, Happiness: 70}
+	if _, _, ok := pichu.NextEvolution(ctx); ok {
+		t.Errorf("pichu should need more happiness to evolve")
+	}
+	ctx.Happiness = 220
+	if evolution, _, ok := pichu.NextEvolution(ctx); !ok || evolution.Species.Name != "pikachu" {
+		t.Errorf("a happy pichu should evolve into pikachu, got %q", evolution.Species.Name)
+	}
+
+	pikachu, _ := chain.Chain.Find("pikachu")
+	useStone := pokeapi.EvolutionContext{Trigger: "use-item", Item: "thunder-stone"}
+	if evolution, detail, ok := pikachu.NextEvolution(useStone); !ok || evolution.Species.Name != "raichu" || detail.Item.Name != "thunder-stone" {
+		t.Errorf("a thunder stone should evolve pikachu into raichu, got %q", evolution.Species.Name)
+	}
+	if _, ok := chain.Chain.Find("charizard"); ok {
+		t.Errorf("charizard is not in pikachu's chain")
+	}
+	if pikachu.NeedsMoves() {
+		t.Errorf("pikachu's evolution doesn't depend on moves")
+	}
+}
+
+func TestLevelUpMoves(t *testing.T) {
+	eevee := pokeapitest.LearnsAt(pokeapi.PokemonData{Name: "eevee"}, 1, "tackle", "tail-whip")
+	eevee = pokeapitest.LearnsAt(eevee, 15, "baby-doll-eyes")
+	eevee = pokeapitest.LearnsAt(eevee, 45, "last-resort")
+
+	if got := fmt.Sprint(eevee.LevelUpMoves(15)); got != "[tackle tail-whip baby-doll-eyes]" {
+		t.Errorf("LevelUpMoves(15) = %s", got)
+	}
+	if got := eevee.LevelUpMoves(0); len(got) != 0 {
+		t.Errorf("LevelUpMoves(0) = %v; want none", got)
+	}
+	if owned := pokeapi.NewUserPokemon(eevee, 5); owned.Moves != nil || owned.XPToNextLevel == 0 {
+		t.Errorf("NewUserPokemon should drop the learnset and set XP to the next level, got %+v", owned)
+	}
+}
+
+func TestEvolveIntoKeepsTheIndividual(t *testing.T) {
+	stats := pokeapitest.Stats{HP: 45, Attack: 49, Defense: 49, SpecialAttack: 65, SpecialDefense: 65, Speed: 45}
+	bulbasaur := pokeapi.NewUserPokemon(pokeapitest.NewPokemon(1, "bulbasaur", 64, stats, "grass", "poison"), 16)
+	bulbasaur.UID = 7
+	bulbasaur.CurrentXP = 120
+	bulbasaur.CaughtTimestamp = 1000
+	bulbasaur.Gender = "female"
+	ivysaur := pokeapitest.LearnsAt(pokeapitest.NewPokemon(2, "ivysaur", 142, stats, "grass", "poison"), 1, "tackle")
+
+	evolved := bulbasaur.EvolveInto(ivysaur, pokeapi.EvolutionRecord{From: "bulbasaur", To: "ivysaur", Trigger: "level-up", Level: 16, Timestamp: 2000})
+
+	if evolved.Name != "ivysaur" || evolved.ID != 2 || evolved.Moves != nil {
+		t.Errorf("evolved species = %s #%d (moves %v); want ivysaur #2 without its learnset", evolved.Name, evolved.ID, evolved.Moves)
+	}
+	if evolved.UID != 7 || evolved.Level != 16 || evolved.CurrentXP != 120 || evolved.XPToNextLevel != bulbasaur.XPToNextLevel || evolved.CaughtTimestamp != 1000 || evolved.Gender != "female" {
+		t.Errorf("evolution must keep the individual, got %+v", evolved)
+	}
+	if len(evolved.Evolutions) != 1 || evolved.Evolutions[0].To != "ivysaur" {
+		t.Errorf("evolution history = %+v; want the one evolution", evolved.Evolutions)
+	}
+	if bulbasaur.Evolutions != nil {
+		t.Errorf("the original must not change, got history %+v", bulbasaur.Evolutions)
+	}
+}
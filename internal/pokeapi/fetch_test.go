@@ -0,0 +1,251 @@
+package pokeapi_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/voidarchive/pokedex/internal/httpreplay"
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/pokecache"
+)
+
+// fixturesDir holds PokeAPI responses exactly as they were recorded, with:
+//
+//	HTTPREPLAY_RECORD=1 go test ./internal/pokeapi/...
+//
+// PokeAPI's data changes over time, so these tests only check properties every
+// recording has. Tests of a response without a recording are skipped; tests that need
+// particular data build it with pokeapitest instead.
+const fixturesDir = "testdata"
+
+func newReplayClient() pokeapi.Client {
+	return pokeapi.NewClient(pokecache.NewCache(5*time.Minute), pokeapi.WithTransport(httpreplay.FromEnv(fixturesDir)))
+}
+
+// skipIfNotRecorded skips the test when err is a replay of a response that hasn't been
+// recorded yet.
+func skipIfNotRecorded(t *testing.T, err error) {
+	t.Helper()
+	if errors.Is(err, httpreplay.ErrNoFixture) {
+		t.Skipf("no recording: %v", err)
+	}
+}
+
+func TestFetchLocationAreas(t *testing.T) {
+	client := newReplayClient()
+
+	res, err := client.FetchLocationAreas(pokeapi.BaseURL + "/location-area")
+	skipIfNotRecorded(t, err)
+	if err != nil {
+		t.Fatalf("FetchLocationAreas returned error: %v", err)
+	}
+	if len(res.Results) == 0 || res.Results[0].Name == "" {
+		t.Fatalf("expected named location areas, got %v", res.Results)
+	}
+	if res.Next == nil || !strings.HasPrefix(*res.Next, pokeapi.BaseURL+"/location-area?offset=20") {
+		t.Errorf("unexpected next page URL: %v", res.Next)
+	}
+	if res.Previous != nil {
+		t.Errorf("expected no previous page on the first page, got %s", *res.Previous)
+	}
+
+	if _, err := client.FetchLocationAreas(""); err == nil {
+		t.Errorf("expected an error for an empty URL")
+	}
+}
+
+func TestFetchLocationAreaDetail(t *testing.T) {
+	client := newReplayClient()
+
+	detail, err := client.FetchLocationAreaDetail("canalave-city-area")
+	skipIfNotRecorded(t, err)
+	if err != nil {
+		t.Fatalf("FetchLocationAreaDetail returned error: %v", err)
+	}
+	if detail.Name != "canalave-city-area" || len(detail.PokemonEncounters) == 0 {
+		t.Fatalf("area = %s with %d encounters; want canalave-city-area with some", detail.Name, len(detail.PokemonEncounters))
+	}
+	for _, encounter := range detail.PokemonEncounters {
+		minLevel, maxLevel, ok := encounter.LevelRange()
+		if encounter.Pokemon.Name == "" || !ok || minLevel > maxLevel || len(encounter.Methods()) == 0 {
+			t.Errorf("encounter %q: levels %d-%d (%v), methods %v", encounter.Pokemon.Name, minLevel, maxLevel, ok, encounter.Methods())
+		}
+	}
+
+	_, err = client.FetchLocationAreaDetail("unknown-area")
+	skipIfNotRecorded(t, err)
+	if !errors.Is(err, pokeapi.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an unknown area, got %v", err)
+	}
+}
+
+func TestFetchPokemon(t *testing.T) {
+	client := newReplayClient()
+
+	for _, name := range []string{"pikachu", "magikarp"} {
+		t.Run(name, func(t *testing.T) {
+			data, err := client.FetchPokemon(name)
+			skipIfNotRecorded(t, err)
+			if err != nil {
+				t.Fatalf("FetchPokemon(%s) returned error: %v", name, err)
+			}
+			if data.ID == 0 || data.Name != name || data.BaseExperience == 0 {
+				t.Errorf("unexpected Pokemon: id=%d name=%s base XP=%d", data.ID, data.Name, data.BaseExperience)
+			}
+			if hp, ok := data.GetStat("hp"); !ok || hp == 0 {
+				t.Errorf("hp = %d, %v; want a base stat", hp, ok)
+			}
+			if len(data.Types) == 0 || data.Types[0].Type.Name == "" {
+				t.Errorf("types = %v; want at least one", data.Types)
+			}
+		})
+	}
+
+	_, err := client.FetchPokemon("missingno")
+	skipIfNotRecorded(t, err)
+	if !errors.Is(err, pokeapi.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an unknown Pokemon, got %v", err)
+	}
+}
+
+func TestFetchPokemonSpecies(t *testing.T) {
+	client := newReplayClient()
+
+	species, err := client.FetchPokemonSpecies("pikachu")
+	skipIfNotRecorded(t, err)
+	if err != nil {
+		t.Fatalf("FetchPokemonSpecies returned error: %v", err)
+	}
+	if species.ID == 0 || species.Name != "pikachu" {
+		t.Errorf("unexpected species: id=%d name=%s", species.ID, species.Name)
+	}
+	if !strings.HasPrefix(species.EvolutionChain.URL, pokeapi.BaseURL+"/evolution-chain/") {
+		t.Errorf("EvolutionChain.URL = %s; want an evolution chain", species.EvolutionChain.URL)
+	}
+	if species.CaptureRate < 1 || species.CaptureRate > 255 || species.Generation.Name == "" || species.GrowthRate.Name == "" {
+		t.Errorf("capture rate %d, generation %q, growth rate %q", species.CaptureRate, species.Generation.Name, species.GrowthRate.Name)
+	}
+	if genus, ok := species.Genus("en"); !ok || genus == "" {
+		t.Errorf("Genus(en) = %q, %v; want an English genus", genus, ok)
+	}
+	if text, version, ok := species.FlavorText("en"); !ok || text == "" || version == "" {
+		t.Errorf("FlavorText(en) = %q from %q, %v; want an English description", text, version, ok)
+	}
+
+	_, err = client.FetchPokemonSpecies("missingno")
+	skipIfNotRecorded(t, err)
+	if !errors.Is(err, pokeapi.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an unknown species, got %v", err)
+	}
+}
+
+func TestFetchEvolutionChain(t *testing.T) {
+	client := newReplayClient()
+
+	chain, err := client.FetchEvolutionChain(pokeapi.BaseURL + "/evolution-chain/10/")
+	skipIfNotRecorded(t, err)
+	if err != nil {
+		t.Fatalf("FetchEvolutionChain returned error: %v", err)
+	}
+	pikachu, ok := chain.Chain.Find("pikachu")
+	if !ok {
+		t.Fatalf("pikachu not found in its own chain, %+v", chain.Chain)
+	}
+	if len(pikachu.EvolvesTo) == 0 {
+		t.Fatalf("pikachu should evolve further")
+	}
+	for _, next := range pikachu.EvolvesTo {
+		if next.Species.Name == "" || len(next.EvolutionDetails) == 0 || next.EvolutionDetails[0].Trigger.Name == "" {
+			t.Errorf("evolution %q has details %+v; want a trigger", next.Species.Name, next.EvolutionDetails)
+		}
+	}
+
+	if _, err := client.FetchEvolutionChain(""); err == nil {
+		t.Errorf("expected an error for an empty URL")
+	}
+}
+
+func TestFetchRegionAndLocation(t *testing.T) {
+	client := newReplayClient()
+
+	region, err := client.FetchRegion("sinnoh")
+	skipIfNotRecorded(t, err)
+	if err != nil {
+		t.Fatalf("FetchRegion returned error: %v", err)
+	}
+	if region.Name != "sinnoh" || len(region.Locations) == 0 {
+		t.Fatalf("region = %s with locations %v; want sinnoh with some", region.Name, region.Locations)
+	}
+
+	location, err := client.FetchLocation("canalave-city")
+	skipIfNotRecorded(t, err)
+	if err != nil {
+		t.Fatalf("FetchLocation returned error: %v", err)
+	}
+	if location.RegionName() != "sinnoh" {
+		t.Errorf("canalave-city region = %q; want sinnoh", location.RegionName())
+	}
+	for _, area := range location.Areas {
+		if !strings.HasPrefix(area.URL, pokeapi.BaseURL+"/location-area/") {
+			t.Errorf("area %s has URL %s; want a location area", area.Name, area.URL)
+		}
+	}
+
+	_, err = client.FetchRegion("atlantis")
+	skipIfNotRecorded(t, err)
+	if !errors.Is(err, pokeapi.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an unknown region, got %v", err)
+	}
+}
+
+func TestFetchPokedexAndGeneration(t *testing.T) {
+	client := newReplayClient()
+
+	pokedex, err := client.FetchPokedex("kanto")
+	skipIfNotRecorded(t, err)
+	if err != nil {
+		t.Fatalf("FetchPokedex returned error: %v", err)
+	}
+	if pokedex.Region == nil || pokedex.Region.Name != "kanto" || len(pokedex.PokemonEntries) == 0 {
+		t.Fatalf("pokedex = %s in %v with %d entries; want kanto's", pokedex.Name, pokedex.Region, len(pokedex.PokemonEntries))
+	}
+	if first := pokedex.PokemonEntries[0]; first.EntryNumber != 1 || first.PokemonSpecies.Name == "" {
+		t.Errorf("first entry = #%d %q; want #1", first.EntryNumber, first.PokemonSpecies.Name)
+	}
+
+	generation, err := client.FetchGeneration("generation-i")
+	skipIfNotRecorded(t, err)
+	if err != nil {
+		t.Fatalf("FetchGeneration returned error: %v", err)
+	}
+	if generation.MainRegion.Name != "kanto" || len(generation.PokemonSpecies) == 0 {
+		t.Errorf("generation = %s in %s with %d species; want generation-i in kanto", generation.Name, generation.MainRegion.Name, len(generation.PokemonSpecies))
+	}
+}
+
+func TestFetchServesRepeatRequestsFromCache(t *testing.T) {
+	server := newBlockingServer()
+	close(server.release)
+	client := pokeapi.NewClient(pokecache.NewCache(5*time.Minute), pokeapi.WithTransport(handlerTransport{server}))
+
+	for range 3 {
+		if _, err := client.FetchPokemon("pikachu"); err != nil {
+			t.Fatalf("FetchPokemon returned error: %v", err)
+		}
+	}
+	if hits := server.hits["/api/v2/pokemon/pikachu"]; hits != 1 {
+		t.Errorf("expected 1 request to reach the server, got %d", hits)
+	}
+}
+
+// skipIfNotRecorded relies on the replay error surviving the client's error wrapping.
+func TestReplayWithoutRecordingReportsIt(t *testing.T) {
+	client := pokeapi.NewClient(pokecache.NewCache(5*time.Minute), pokeapi.WithTransport(httpreplay.NewReplayer(t.TempDir())))
+
+	_, err := client.FetchPokemon("pikachu")
+	if !errors.Is(err, httpreplay.ErrNoFixture) || errors.Is(err, pokeapi.ErrNotFound) {
+		t.Errorf("got %v; want ErrNoFixture", err)
+	}
+}
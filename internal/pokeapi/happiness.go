@@ -0,0 +1,45 @@
+package pokeapi
+
+// Happiness (or friendship) runs from 0 to MaxHappiness. A caught Pokemon starts at its
+// species' base happiness; DefaultHappiness is the base of most species.
+const (
+	MaxHappiness     = 255
+	DefaultHappiness = 70
+)
+
+// sootheBell is the held item that makes a Pokemon grow happier faster.
+const sootheBell = "soothe-bell"
+
+// HappinessChange is how much an event changes a Pokemon's happiness, depending on how
+// happy it already is: below 100, from 100 to 199, and 200 or more. Like in the games,
+// a happy Pokemon gets happier more slowly.
+type HappinessChange [3]int
+
+var (
+	HappinessLevelUp = HappinessChange{5, 3, 2}
+	HappinessWalk    = HappinessChange{1, 1, 1} // Walking to another location in the party
+	HappinessVictory = HappinessChange{1, 1, 1} // Winning a battle
+	HappinessFaint   = HappinessChange{-1, -1, -1}
+	HappinessBerry   = HappinessChange{10, 5, 2} // Pomeg, Kelpsy, Qualot, Hondew, Grepa and Tamato Berries
+)
+
+// ChangeHappiness applies change to the Pokemon's happiness, keeping it between 0 and
+// MaxHappiness, and returns by how much it actually changed. Holding a Soothe Bell
+// makes gains half again as big, rounded up so that +1 gains become +2.
+func (up *UserPokemon) ChangeHappiness(change HappinessChange) int {
+	tier := 0
+	switch {
+	case up.Happiness >= 200:
+		tier = 2
+	case up.Happiness >= 100:
+		tier = 1
+	}
+	amount := change[tier]
+	if amount > 0 && up.HeldItem == sootheBell {
+		amount = (amount*3 + 1) / 2
+	}
+
+	before := up.Happiness
+	up.Happiness = min(max(up.Happiness+amount, 0), MaxHappiness)
+	return up.Happiness - before
+}
@@ -0,0 +1,36 @@
+package pokeapi_test
+
+import (
+	"testing"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+)
+
+func TestChangeHappiness(t *testing.T) {
+	tests := []struct {
+		name      string
+		happiness int
+		heldItem  string
+		change    pokeapi.HappinessChange
+		want      int
+	}{
+		{name: "low", happiness: 70, change: pokeapi.HappinessLevelUp, want: 75},
+		{name: "middle", happiness: 150, change: pokeapi.HappinessLevelUp, want: 153},
+		{name: "high", happiness: 200, change: pokeapi.HappinessLevelUp, want: 202},
+		{name: "capped", happiness: 254, change: pokeapi.HappinessBerry, want: pokeapi.MaxHappiness},
+		{name: "soothe bell", happiness: 70, heldItem: "soothe-bell", change: pokeapi.HappinessBerry, want: 85},
+		{name: "soothe bell rounds small gains up", happiness: 70, heldItem: "soothe-bell", change: pokeapi.HappinessWalk, want: 72},
+		{name: "soothe bell doesn't add to losses", happiness: 70, heldItem: "soothe-bell", change: pokeapi.HappinessFaint, want: 69},
+		{name: "not below zero", happiness: 0, change: pokeapi.HappinessFaint, want: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := pokeapi.UserPokemon{Happiness: tc.happiness, HeldItem: tc.heldItem}
+			delta := p.ChangeHappiness(tc.change)
+			if p.Happiness != tc.want || delta != tc.want-tc.happiness {
+				t.Errorf("happiness = %d (changed by %d); want %d", p.Happiness, delta, tc.want)
+			}
+		})
+	}
+}
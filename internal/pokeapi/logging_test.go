@@ -0,0 +1,50 @@
+package pokeapi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/pokecache"
+)
+
+func TestClientLogsRequestsAndCacheHits(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	server := &etagServer{}
+	client := pokeapi.NewClient(pokecache.NewCache(5*time.Minute), pokeapi.WithTransport(handlerTransport{server}), pokeapi.WithLogger(logger))
+
+	for range 2 {
+		if _, err := client.FetchPokemon("pikachu"); err != nil {
+			t.Fatalf("FetchPokemon returned error: %v", err)
+		}
+	}
+
+	var records []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var record map[string]any
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("log line is not JSON: %s", line)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 log records, got %d:\n%s", len(records), buf.String())
+	}
+
+	wantURL := pokeapi.BaseURL + "/pokemon/pikachu"
+	miss, hit := records[0], records[1]
+	if miss["url"] != wantURL || miss["cache_hit"] != false || miss["status"] != float64(http.StatusOK) {
+		t.Errorf("unexpected record for the network request: %v", miss)
+	}
+	if _, ok := miss["duration"]; !ok {
+		t.Errorf("expected a duration field, got %v", miss)
+	}
+	if hit["url"] != wantURL || hit["cache_hit"] != true {
+		t.Errorf("unexpected record for the cache hit: %v", hit)
+	}
+}
@@ -0,0 +1,68 @@
+package pokeapi
+
+import (
+	"fmt"
+	"slices"
+)
+
+// PokemonMove is a move in a Pokemon's learnset, with how it is learned in each
+// version group.
+type PokemonMove struct {
+	Move                NamedAPIResource         `json:"move"`
+	VersionGroupDetails []PokemonMoveVersionInfo `json:"version_group_details"`
+}
+
+// PokemonMoveVersionInfo says how a move is learned in one version group.
+type PokemonMoveVersionInfo struct {
+	LevelLearnedAt  int              `json:"level_learned_at"`
+	MoveLearnMethod NamedAPIResource `json:"move_learn_method"` // e.g. "level-up", "machine", "egg"
+	VersionGroup    NamedAPIResource `json:"version_group"`
+}
+
+// LevelUpMoves returns the moves a Pokemon of this species has learned by leveling up
+// to level, in any version group, ordered by the level they're learned at.
+func (pd PokemonData) LevelUpMoves(level int) []string {
+	type learned struct {
+		name  string
+		level int
+	}
+	var moves []learned
+	for _, m := range pd.Moves {
+		at := -1
+		for _, detail := range m.VersionGroupDetails {
+			if detail.MoveLearnMethod.Name == "level-up" && detail.LevelLearnedAt <= level && (at < 0 || detail.LevelLearnedAt < at) {
+				at = detail.LevelLearnedAt
+			}
+		}
+		if at >= 0 {
+			moves = append(moves, learned{m.Move.Name, at})
+		}
+	}
+	slices.SortStableFunc(moves, func(a, b learned) int { return a.level - b.level })
+
+	names := make([]string, len(moves))
+	for i, m := range moves {
+		names[i] = m.name
+	}
+	return names
+}
+
+// Move represents data from the /move/{id_or_name}/ endpoint.
+type Move struct {
+	ID   int              `json:"id"`
+	Name string           `json:"name"`
+	Type NamedAPIResource `json:"type"`
+}
+
+// FetchMove fetches a move by name or ID.
+func (c *Client) FetchMove(moveName string) (Move, error) {
+	url := fmt.Sprintf("%s/move/%s", BaseURL, moveName)
+
+	return coalesce(c, url, func() (Move, error) {
+		body, err := c.get(url)
+		if err != nil {
+			return Move{}, resourceError("move", moveName, err)
+		}
+		return decode[Move](url, body)
+	})
+}
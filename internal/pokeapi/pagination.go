@@ -0,0 +1,156 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// DefaultPageLimit is the page size PokeAPI uses when no limit is given.
+const DefaultPageLimit = 20
+
+// NamedAPIResource is PokeAPI's {name, url} reference to another resource.
+type NamedAPIResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// NamedAPIResourceList is one page of a list endpoint such as /pokemon, /item,
+// /move, /type or /region.
+type NamedAPIResourceList struct {
+	Count    int                `json:"count"`
+	Next     *string            `json:"next"`
+	Previous *string            `json:"previous"`
+	Results  []NamedAPIResource `json:"results"`
+}
+
+// FetchResourceList fetches a single page of a list endpoint by its full URL,
+// e.g. the Next URL of a previous page. Concurrent callers share the request, so
+// cancelling ctx only stops this caller from waiting for it.
+func (c *Client) FetchResourceList(ctx context.Context, url string) (NamedAPIResourceList, error) {
+	if url == "" {
+		return NamedAPIResourceList{}, fmt.Errorf("cannot fetch resource list from empty URL")
+	}
+
+	return coalesceContext(ctx, c, url, func(ctx context.Context) (NamedAPIResourceList, error) {
+		body, err := c.getContext(ctx, url)
+		if err != nil {
+			return NamedAPIResourceList{}, resourceError("resource list", url, err)
+		}
+		return decode[NamedAPIResourceList](url, body)
+	})
+}
+
+type pageConfig struct {
+	offset   int
+	prefetch bool
+}
+
+// PageOption customizes Pages and All.
+type PageOption func(*pageConfig)
+
+// WithOffset starts the walk at the given item index instead of the first item.
+func WithOffset(offset int) PageOption {
+	return func(p *pageConfig) {
+		p.offset = offset
+	}
+}
+
+// WithPrefetch fetches the next page in the background while the current one is
+// being consumed, so slow consumers don't wait on the network between pages.
+func WithPrefetch() PageOption {
+	return func(p *pageConfig) {
+		p.prefetch = true
+	}
+}
+
+// ListURL returns the URL of the first page of the list endpoint at path
+// (e.g. "pokemon" or "/move") with the given offset and limit.
+func ListURL(path string, offset, limit int) string {
+	if limit <= 0 {
+		limit = DefaultPageLimit
+	}
+	return fmt.Sprintf("%s/%s?offset=%d&limit=%d", BaseURL, strings.Trim(path, "/"), offset, limit)
+}
+
+type pageResult struct {
+	page NamedAPIResourceList
+	err  error
+}
+
+// Pages walks the list endpoint at path (e.g. "pokemon", "item", "move", "type",
+// "region"), limit resources at a time, following each page's Next URL.
+// Iteration stops after the last page, when the caller breaks out of the loop, or
+// after the first error, which is yielded together with an empty page.
+//
+//	for page, err := range client.Pages(ctx, "type", 50) {
+//		if err != nil { ... }
+//		for _, t := range page.Results { ... }
+//	}
+func (c *Client) Pages(ctx context.Context, path string, limit int, opts ...PageOption) iter.Seq2[NamedAPIResourceList, error] {
+	cfg := pageConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	firstURL := ListURL(path, cfg.offset, limit)
+
+	return func(yield func(NamedAPIResourceList, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel() // abandons a pending prefetch if the caller stops early
+
+		fetch := func(url string) <-chan pageResult {
+			ch := make(chan pageResult, 1)
+			go func() {
+				page, err := c.FetchResourceList(ctx, url)
+				ch <- pageResult{page, err}
+			}()
+			return ch
+		}
+
+		next := fetch(firstURL)
+		for next != nil {
+			var res pageResult
+			select {
+			case res = <-next:
+			case <-ctx.Done():
+				yield(NamedAPIResourceList{}, ctx.Err())
+				return
+			}
+			if res.err != nil {
+				yield(NamedAPIResourceList{}, res.err)
+				return
+			}
+
+			next = nil
+			hasNext := res.page.Next != nil && *res.page.Next != ""
+			if hasNext && cfg.prefetch {
+				next = fetch(*res.page.Next)
+			}
+			if !yield(res.page, nil) {
+				return
+			}
+			if hasNext && next == nil {
+				next = fetch(*res.page.Next)
+			}
+		}
+	}
+}
+
+// All walks every resource of the list endpoint at path, fetching pages of limit
+// resources as needed. It accepts the same options as Pages.
+func (c *Client) All(ctx context.Context, path string, limit int, opts ...PageOption) iter.Seq2[NamedAPIResource, error] {
+	return func(yield func(NamedAPIResource, error) bool) {
+		for page, err := range c.Pages(ctx, path, limit, opts...) {
+			if err != nil {
+				yield(NamedAPIResource{}, err)
+				return
+			}
+			for _, resource := range page.Results {
+				if !yield(resource, nil) {
+					return
+				}
+			}
+		}
+	}
+}
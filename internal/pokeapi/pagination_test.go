@@ -0,0 +1,177 @@
+package pokeapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/pokecache"
+)
+
+// listServer serves a /api/v2/pokemon list endpoint with total resources named
+// "pokemon-0", "pokemon-1", ... and records the offset of every request it receives.
+type listServer struct {
+	total int
+
+	mu      sync.Mutex
+	offsets []int
+}
+
+func (s *listServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/api/v2/pokemon" {
+		http.NotFound(w, r)
+		return
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	s.mu.Lock()
+	s.offsets = append(s.offsets, offset)
+	s.mu.Unlock()
+
+	page := pokeapi.NamedAPIResourceList{Count: s.total}
+	for i := offset; i < offset+limit && i < s.total; i++ {
+		page.Results = append(page.Results, pokeapi.NamedAPIResource{
+			Name: fmt.Sprintf("pokemon-%d", i),
+			URL:  fmt.Sprintf("%s/pokemon/%d/", pokeapi.BaseURL, i+1),
+		})
+	}
+	if offset+limit < s.total {
+		next := pokeapi.ListURL("pokemon", offset+limit, limit)
+		page.Next = &next
+	}
+	if offset > 0 {
+		prev := pokeapi.ListURL("pokemon", max(offset-limit, 0), limit)
+		page.Previous = &prev
+	}
+	json.NewEncoder(w).Encode(page)
+}
+
+func (s *listServer) requestedOffsets() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int(nil), s.offsets...)
+}
+
+func newListClient(server *listServer) pokeapi.Client {
+	return pokeapi.NewClient(pokecache.NewCache(5*time.Minute), pokeapi.WithTransport(handlerTransport{server}))
+}
+
+func TestAllWalksEveryPage(t *testing.T) {
+	server := &listServer{total: 45}
+	client := newListClient(server)
+
+	var names []string
+	for resource, err := range client.All(context.Background(), "pokemon", 20) {
+		if err != nil {
+			t.Fatalf("All returned error: %v", err)
+		}
+		names = append(names, resource.Name)
+	}
+
+	if len(names) != 45 {
+		t.Fatalf("expected 45 resources, got %d", len(names))
+	}
+	for i, name := range names {
+		if want := fmt.Sprintf("pokemon-%d", i); name != want {
+			t.Fatalf("resource %d = %s; want %s", i, name, want)
+		}
+	}
+	if got := server.requestedOffsets(); fmt.Sprint(got) != "[0 20 40]" {
+		t.Errorf("requested offsets = %v; want [0 20 40]", got)
+	}
+}
+
+func TestPagesWithOffsetAndLimit(t *testing.T) {
+	server := &listServer{total: 45}
+	client := newListClient(server)
+
+	var sizes []int
+	for page, err := range client.Pages(context.Background(), "/pokemon/", 10, pokeapi.WithOffset(30)) {
+		if err != nil {
+			t.Fatalf("Pages returned error: %v", err)
+		}
+		if page.Count != 45 {
+			t.Errorf("page.Count = %d; want 45", page.Count)
+		}
+		sizes = append(sizes, len(page.Results))
+	}
+
+	if fmt.Sprint(sizes) != "[10 5]" {
+		t.Errorf("page sizes = %v; want [10 5]", sizes)
+	}
+}
+
+func TestPagesStopsWhenTheCallerBreaks(t *testing.T) {
+	server := &listServer{total: 100}
+	client := newListClient(server)
+
+	for page, err := range client.Pages(context.Background(), "pokemon", 20) {
+		if err != nil {
+			t.Fatalf("Pages returned error: %v", err)
+		}
+		if page.Results[0].Name != "pokemon-0" {
+			t.Errorf("first page starts at %s", page.Results[0].Name)
+		}
+		break
+	}
+
+	if got := server.requestedOffsets(); len(got) != 1 {
+		t.Errorf("expected only the first page to be fetched without prefetch, got offsets %v", got)
+	}
+}
+
+func TestPagesPrefetchesTheNextPage(t *testing.T) {
+	server := &listServer{total: 60}
+	client := newListClient(server)
+
+	pages := 0
+	for _, err := range client.Pages(context.Background(), "pokemon", 20, pokeapi.WithPrefetch()) {
+		if err != nil {
+			t.Fatalf("Pages returned error: %v", err)
+		}
+		pages++
+		if pages == 1 {
+			// The second page is requested while we're still looking at the first one.
+			deadline := time.Now().Add(time.Second)
+			for len(server.requestedOffsets()) < 2 && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			if got := server.requestedOffsets(); len(got) < 2 || got[1] != 20 {
+				t.Fatalf("expected the next page to be prefetched, offsets so far: %v", got)
+			}
+		}
+	}
+	if pages != 3 {
+		t.Errorf("expected 3 pages, got %d", pages)
+	}
+}
+
+func TestPagesYieldsErrors(t *testing.T) {
+	client := newListClient(&listServer{total: 10})
+
+	var gotErr error
+	for _, err := range client.All(context.Background(), "moves-that-dont-exist", 20) {
+		gotErr = err
+	}
+	if !errors.Is(gotErr, pokeapi.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an unknown endpoint, got %v", gotErr)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	gotErr = nil
+	for _, err := range client.Pages(ctx, "pokemon", 20) {
+		gotErr = err
+	}
+	if !errors.Is(gotErr, context.Canceled) {
+		t.Errorf("expected context.Canceled for a cancelled context, got %v", gotErr)
+	}
+}
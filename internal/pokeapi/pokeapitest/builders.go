@@ -0,0 +1,217 @@
+package pokeapitest
+
+import (
+	"fmt"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+)
+
+// Stats lists the six base stats of a Pokemon in the order PokeAPI returns them.
+type Stats struct {
+	HP, Attack, Defense, SpecialAttack, SpecialDefense, Speed int
+}
+
+// NewPokemon builds PokemonData without spelling out the anonymous stat and type structs.
+func NewPokemon(id int, name string, baseExperience int, stats Stats, types ...string) pokeapi.PokemonData {
+	p := pokeapi.PokemonData{
+		ID:             id,
+		Name:           name,
+		BaseExperience: baseExperience,
+		Species: pokeapi.NamedAPIResource{
+			Name: name,
+			URL:  fmt.Sprintf("%s/pokemon-species/%d/", pokeapi.BaseURL, id),
+		},
+	}
+
+	statValues := []struct {
+		name  string
+		value int
+	}{
+		{"hp", stats.HP},
+		{"attack", stats.Attack},
+		{"defense", stats.Defense},
+		{"special-attack", stats.SpecialAttack},
+		{"special-defense", stats.SpecialDefense},
+		{"speed", stats.Speed},
+	}
+	p.Stats = make([]struct {
+		BaseStat int `json:"base_stat"`
+		Effort   int `json:"effort"`
+		Stat     struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"stat"`
+	}, len(statValues))
+	for i, s := range statValues {
+		p.Stats[i].BaseStat = s.value
+		p.Stats[i].Stat.Name = s.name
+		p.Stats[i].Stat.URL = fmt.Sprintf("%s/stat/%d/", pokeapi.BaseURL, i+1)
+	}
+
+	p.Types = make([]struct {
+		Slot int `json:"slot"`
+		Type struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"type"`
+	}, len(types))
+	for i, t := range types {
+		p.Types[i].Slot = i + 1
+		p.Types[i].Type.Name = t
+		p.Types[i].Type.URL = fmt.Sprintf("%s/type/%s/", pokeapi.BaseURL, t)
+	}
+	return p
+}
+
+// Link builds one species in an evolution chain. details describe how the
+// previous species evolves into this one; evolvesTo lists the next stages.
+func Link(species string, details []pokeapi.EvolutionDetail, evolvesTo ...pokeapi.CorrectedChainLink) pokeapi.CorrectedChainLink {
+	return pokeapi.CorrectedChainLink{
+		Species: pokeapi.Pokemon{
+			Name: species,
+			URL:  fmt.Sprintf("%s/pokemon-species/%s/", pokeapi.BaseURL, species),
+		},
+		EvolutionDetails: details,
+		EvolvesTo:        evolvesTo,
+	}
+}
+
+// LevelUp returns the evolution details of a plain "level-up at minLevel" evolution.
+func LevelUp(minLevel int) []pokeapi.EvolutionDetail {
+	detail := pokeapi.EvolutionDetail{MinLevel: &minLevel}
+	detail.Trigger.Name = "level-up"
+	return []pokeapi.EvolutionDetail{detail}
+}
+
+// LevelUpAt returns the evolution details of a level-up evolution that only happens at
+// the given time of day ("day" or "night"), like Eevee's into Espeon and Umbreon.
+func LevelUpAt(timeOfDay string) []pokeapi.EvolutionDetail {
+	detail := pokeapi.EvolutionDetail{TimeOfDay: timeOfDay}
+	detail.Trigger.Name = "level-up"
+	return []pokeapi.EvolutionDetail{detail}
+}
+
+// UseItem returns the evolution details of an evolution by using item on the Pokemon,
+// like a thunder-stone on Pikachu.
+func UseItem(item string) []pokeapi.EvolutionDetail {
+	detail := pokeapi.EvolutionDetail{Item: &pokeapi.NamedAPIResource{Name: item, URL: fmt.Sprintf("%s/item/%s/", pokeapi.BaseURL, item)}}
+	detail.Trigger.Name = "use-item"
+	return []pokeapi.EvolutionDetail{detail}
+}
+
+// Trade returns the evolution details of an evolution by trading the Pokemon, holding
+// heldItem and for tradeSpecies unless they're empty, like Onix's into Steelix holding
+// a metal-coat or Karrablast's into Escavalier for a Shelmet.
+func Trade(heldItem, tradeSpecies string) []pokeapi.EvolutionDetail {
+	detail := pokeapi.EvolutionDetail{}
+	detail.Trigger.Name = "trade"
+	if heldItem != "" {
+		detail.HeldItem = &pokeapi.NamedAPIResource{Name: heldItem, URL: fmt.Sprintf("%s/item/%s/", pokeapi.BaseURL, heldItem)}
+	}
+	if tradeSpecies != "" {
+		detail.TradeSpecies = &pokeapi.NamedAPIResource{Name: tradeSpecies, URL: fmt.Sprintf("%s/pokemon-species/%s/", pokeapi.BaseURL, tradeSpecies)}
+	}
+	return []pokeapi.EvolutionDetail{detail}
+}
+
+// Move builds a move of the given type.
+func Move(name, moveType string) pokeapi.Move {
+	return pokeapi.Move{Name: name, Type: pokeapi.NamedAPIResource{Name: moveType, URL: fmt.Sprintf("%s/type/%s/", pokeapi.BaseURL, moveType)}}
+}
+
+// LearnsAt adds moves learned by leveling up to level to a Pokemon's learnset.
+func LearnsAt(p pokeapi.PokemonData, level int, moves ...string) pokeapi.PokemonData {
+	for _, name := range moves {
+		p.Moves = append(p.Moves, pokeapi.PokemonMove{
+			Move: pokeapi.NamedAPIResource{Name: name, URL: fmt.Sprintf("%s/move/%s/", pokeapi.BaseURL, name)},
+			VersionGroupDetails: []pokeapi.PokemonMoveVersionInfo{{
+				LevelLearnedAt:  level,
+				MoveLearnMethod: pokeapi.NamedAPIResource{Name: "level-up"},
+				VersionGroup:    pokeapi.NamedAPIResource{Name: "scarlet-violet"},
+			}},
+		})
+	}
+	return p
+}
+
+// ChainURL returns the PokeAPI URL of the evolution chain with the given ID.
+func ChainURL(id int) string {
+	return fmt.Sprintf("%s/evolution-chain/%d/", pokeapi.BaseURL, id)
+}
+
+// Area builds a location area in which the given Pokemon can be encountered.
+func Area(name string, encounters ...pokeapi.PokemonEncounter) pokeapi.LocationAreaDetail {
+	return pokeapi.LocationAreaDetail{Name: name, PokemonEncounters: encounters}
+}
+
+// Location builds a location made up of the named areas. Register it with Fake.AddRegion.
+func Location(name string, areas ...string) pokeapi.Location {
+	location := pokeapi.Location{Name: name}
+	for _, area := range areas {
+		location.Areas = append(location.Areas, pokeapi.NamedAPIResource{
+			Name: area,
+			URL:  fmt.Sprintf("%s/location-area/%s/", pokeapi.BaseURL, area),
+		})
+	}
+	return location
+}
+
+// Encounter builds an area encounter with pokemon found by walking in tall grass
+// between minLevel and maxLevel.
+func Encounter(pokemon string, minLevel, maxLevel int) pokeapi.PokemonEncounter {
+	return EncounterBy(pokemon, "walk", 100, minLevel, maxLevel)
+}
+
+// EncounterBy builds an area encounter with pokemon found by method with a percent
+// chance between minLevel and maxLevel, under the given conditions (e.g. "time-night").
+func EncounterBy(pokemon, method string, chance, minLevel, maxLevel int, conditions ...string) pokeapi.PokemonEncounter {
+	detail := pokeapi.Encounter{
+		MinLevel: minLevel,
+		MaxLevel: maxLevel,
+		Chance:   chance,
+		Method:   pokeapi.NamedAPIResource{Name: method},
+	}
+	for _, condition := range conditions {
+		detail.ConditionValues = append(detail.ConditionValues, pokeapi.NamedAPIResource{Name: condition})
+	}
+	return pokeapi.PokemonEncounter{
+		Pokemon: pokeapi.Pokemon{
+			Name: pokemon,
+			URL:  fmt.Sprintf("%s/pokemon/%s/", pokeapi.BaseURL, pokemon),
+		},
+		VersionDetails: []pokeapi.VersionEncounterDetail{{
+			Version:          pokeapi.NamedAPIResource{Name: "diamond"},
+			MaxChance:        chance,
+			EncounterDetails: []pokeapi.Encounter{detail},
+		}},
+	}
+}
+
+// Pokedex builds a Pokedex listing species numbered consecutively from first, e.g.
+// Pokedex("national", "", 10, "caterpie", "metapod"). Leave region empty for the
+// national Pokedex.
+func Pokedex(name, region string, first int, species ...string) pokeapi.Pokedex {
+	pokedex := pokeapi.Pokedex{Name: name, IsMainSeries: true}
+	if region != "" {
+		pokedex.Region = &pokeapi.NamedAPIResource{Name: region, URL: fmt.Sprintf("%s/region/%s/", pokeapi.BaseURL, region)}
+	}
+	for i, s := range species {
+		pokedex.PokemonEntries = append(pokedex.PokemonEntries, pokeapi.PokedexEntry{
+			EntryNumber:    first + i,
+			PokemonSpecies: pokeapi.NamedAPIResource{Name: s, URL: fmt.Sprintf("%s/pokemon-species/%s/", pokeapi.BaseURL, s)},
+		})
+	}
+	return pokedex
+}
+
+// Generation builds a generation whose main region is region, introducing species.
+func Generation(name, region string, species ...string) pokeapi.Generation {
+	generation := pokeapi.Generation{
+		Name:       name,
+		MainRegion: pokeapi.NamedAPIResource{Name: region, URL: fmt.Sprintf("%s/region/%s/", pokeapi.BaseURL, region)},
+	}
+	for _, s := range species {
+		generation.PokemonSpecies = append(generation.PokemonSpecies, pokeapi.NamedAPIResource{Name: s, URL: fmt.Sprintf("%s/pokemon-species/%s/", pokeapi.BaseURL, s)})
+	}
+	return generation
+}
@@ -0,0 +1,424 @@
+// Package pokeapitest provides an in-memory stand-in for pokeapi.Client,
+// seeded from plain Go structs, for tests that shouldn't need recorded HTTP fixtures.
+package pokeapitest
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+)
+
+// Fake implements the same Fetch methods as pokeapi.Client, serving data from maps.
+// Lookups for anything that hasn't been added fail with the same typed "not found"
+// errors the real client returns (errors.Is(err, pokeapi.ErrNotFound)). It is safe for concurrent use.
+type Fake struct {
+	mu sync.Mutex
+
+	locationAreaPages map[string]pokeapi.LocationAreasResponse  // keyed by page URL
+	locationAreas     map[string]pokeapi.LocationAreaDetail     // keyed by area name
+	pokemon           map[string]pokeapi.PokemonData            // keyed by name and ID
+	species           map[string]pokeapi.PokemonSpecies         // keyed by name and ID
+	evolutionChains   map[string]pokeapi.EvolutionChainResponse // keyed by chain URL
+	regions           map[string]pokeapi.Region                 // keyed by region name
+	regionOrder       []string                                  // region names in the order they were added
+	locations         map[string]pokeapi.Location               // keyed by location name
+	moves             map[string]pokeapi.Move                   // keyed by move name
+	pokedexes         map[string]pokeapi.Pokedex                // keyed by Pokedex name
+	generations       map[string]pokeapi.Generation             // keyed by generation name
+
+	errs  map[string]error // keyed by the argument passed to a Fetch method
+	calls []string
+}
+
+// NewFake returns an empty Fake.
+func NewFake() *Fake {
+	return &Fake{
+		locationAreaPages: make(map[string]pokeapi.LocationAreasResponse),
+		locationAreas:     make(map[string]pokeapi.LocationAreaDetail),
+		pokemon:           make(map[string]pokeapi.PokemonData),
+		species:           make(map[string]pokeapi.PokemonSpecies),
+		evolutionChains:   make(map[string]pokeapi.EvolutionChainResponse),
+		regions:           make(map[string]pokeapi.Region),
+		locations:         make(map[string]pokeapi.Location),
+		moves:             make(map[string]pokeapi.Move),
+		pokedexes:         make(map[string]pokeapi.Pokedex),
+		generations:       make(map[string]pokeapi.Generation),
+		errs:              make(map[string]error),
+	}
+}
+
+// FailWith makes every Fetch call whose argument is arg (a name, ID or URL) return err,
+// e.g. a *pokeapi.HTTPError with status 429 to simulate rate limiting.
+func (f *Fake) FailWith(arg string, err error) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errs[arg] = err
+	return f
+}
+
+// AddLocationAreaPage registers a page of location areas served for url.
+func (f *Fake) AddLocationAreaPage(url string, page pokeapi.LocationAreasResponse) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.locationAreaPages[url] = page
+	return f
+}
+
+// AddLocationArea registers a location area, looked up by its name.
+func (f *Fake) AddLocationArea(area pokeapi.LocationAreaDetail) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.locationAreas[area.Name] = area
+	return f
+}
+
+// AddPokemon registers Pokemon data, looked up by name or ID.
+func (f *Fake) AddPokemon(pokemon ...pokeapi.PokemonData) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, p := range pokemon {
+		f.pokemon[p.Name] = p
+		if p.ID != 0 {
+			f.pokemon[strconv.Itoa(p.ID)] = p
+		}
+	}
+	return f
+}
+
+// AddSpecies registers species data, looked up by name or ID.
+func (f *Fake) AddSpecies(species ...pokeapi.PokemonSpecies) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, s := range species {
+		f.species[s.Name] = s
+		if s.ID != 0 {
+			f.species[strconv.Itoa(s.ID)] = s
+		}
+	}
+	return f
+}
+
+// AddEvolutionChain registers an evolution chain under url and, for every species in it
+// that has no species data yet, a minimal PokemonSpecies pointing back at the chain.
+func (f *Fake) AddEvolutionChain(url string, chain pokeapi.EvolutionChainResponse) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.evolutionChains[url] = chain
+
+	var register func(link pokeapi.CorrectedChainLink)
+	register = func(link pokeapi.CorrectedChainLink) {
+		if _, ok := f.species[link.Species.Name]; !ok {
+			// The easiest capture rate, so scenarios don't hinge on lucky throws.
+			s := pokeapi.PokemonSpecies{Name: link.Species.Name, CaptureRate: 255}
+			s.EvolutionChain.URL = url
+			f.species[s.Name] = s
+		}
+		for _, next := range link.EvolvesTo {
+			register(next)
+		}
+	}
+	register(chain.Chain)
+	return f
+}
+
+// AddRegion registers a region together with its locations. Each location is linked
+// to the region and added to its location list, and the areas of each location report
+// it as their location when fetched.
+func (f *Fake) AddRegion(region pokeapi.Region, locations ...pokeapi.Location) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	regionRef := pokeapi.NamedAPIResource{Name: region.Name, URL: fmt.Sprintf("%s/region/%s/", pokeapi.BaseURL, region.Name)}
+	for _, location := range locations {
+		location.Region = &regionRef
+		f.locations[location.Name] = location
+		region.Locations = append(region.Locations, pokeapi.NamedAPIResource{
+			Name: location.Name,
+			URL:  fmt.Sprintf("%s/location/%s/", pokeapi.BaseURL, location.Name),
+		})
+	}
+	if _, exists := f.regions[region.Name]; !exists {
+		f.regionOrder = append(f.regionOrder, region.Name)
+	}
+	f.regions[region.Name] = region
+	return f
+}
+
+// AddMove registers moves, e.g. Move("moonblast", "fairy").
+func (f *Fake) AddMove(moves ...pokeapi.Move) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, m := range moves {
+		f.moves[m.Name] = m
+	}
+	return f
+}
+
+// AddPokedex registers Pokedexes, e.g. Pokedex("kanto", "kanto", 1, "bulbasaur"). A
+// regional Pokedex is added to the Pokedexes of its region, if that was added already.
+func (f *Fake) AddPokedex(pokedexes ...pokeapi.Pokedex) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, p := range pokedexes {
+		f.pokedexes[p.Name] = p
+		if p.Region == nil {
+			continue
+		}
+		if region, ok := f.regions[p.Region.Name]; ok {
+			region.Pokedexes = append(region.Pokedexes, pokeapi.NamedAPIResource{Name: p.Name, URL: fmt.Sprintf("%s/pokedex/%s/", pokeapi.BaseURL, p.Name)})
+			f.regions[region.Name] = region
+		}
+	}
+	return f
+}
+
+// AddGeneration registers generations, e.g. Generation("generation-i", "kanto", "bulbasaur").
+// A generation becomes the main generation of its region, if that was added already.
+func (f *Fake) AddGeneration(generations ...pokeapi.Generation) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, g := range generations {
+		f.generations[g.Name] = g
+		if region, ok := f.regions[g.MainRegion.Name]; ok {
+			region.MainGeneration = &pokeapi.NamedAPIResource{Name: g.Name, URL: fmt.Sprintf("%s/generation/%s/", pokeapi.BaseURL, g.Name)}
+			f.regions[region.Name] = region
+		}
+	}
+	return f
+}
+
+// Calls returns every Fetch call made so far, formatted as "Method(arg)".
+func (f *Fake) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// record logs the call and returns the error injected for arg, if any.
+func (f *Fake) record(method, arg string) error {
+	f.calls = append(f.calls, fmt.Sprintf("%s(%s)", method, arg))
+	return f.errs[arg]
+}
+
+// notFound builds the same error the real client returns for a 404.
+func notFound(kind, name, url string) error {
+	return fmt.Errorf("%s '%s' not found: %w", kind, name, &pokeapi.HTTPError{Status: http.StatusNotFound, URL: url})
+}
+
+func (f *Fake) FetchLocationAreas(url string) (pokeapi.LocationAreasResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("FetchLocationAreas", url); err != nil {
+		return pokeapi.LocationAreasResponse{}, err
+	}
+
+	if url == "" {
+		return pokeapi.LocationAreasResponse{}, fmt.Errorf("cannot fetch from empty URL")
+	}
+	page, ok := f.locationAreaPages[url]
+	if !ok {
+		return pokeapi.LocationAreasResponse{}, fmt.Errorf("request for location areas failed: %w", &pokeapi.HTTPError{Status: http.StatusNotFound, URL: url})
+	}
+	return page, nil
+}
+
+func (f *Fake) FetchLocationAreaDetail(areaName string) (pokeapi.LocationAreaDetail, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("FetchLocationAreaDetail", areaName); err != nil {
+		return pokeapi.LocationAreaDetail{}, err
+	}
+
+	area, ok := f.locationAreas[areaName]
+	if !ok {
+		return pokeapi.LocationAreaDetail{}, notFound("location area", areaName, pokeapi.BaseURL+"/location-area/"+areaName)
+	}
+	if area.Location.Name == "" {
+		for _, location := range f.locations {
+			for _, a := range location.Areas {
+				if a.Name == areaName {
+					area.Location = pokeapi.NamedAPIResource{Name: location.Name, URL: fmt.Sprintf("%s/location/%s/", pokeapi.BaseURL, location.Name)}
+				}
+			}
+		}
+	}
+	return area, nil
+}
+
+func (f *Fake) FetchPokemon(pokemonName string) (pokeapi.PokemonData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("FetchPokemon", pokemonName); err != nil {
+		return pokeapi.PokemonData{}, err
+	}
+
+	pokemon, ok := f.pokemon[pokemonName]
+	if !ok {
+		return pokeapi.PokemonData{}, notFound("pokemon", pokemonName, pokeapi.BaseURL+"/pokemon/"+pokemonName)
+	}
+	return pokemon, nil
+}
+
+func (f *Fake) FetchPokemonSpecies(pokemonNameOrID string) (pokeapi.PokemonSpecies, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("FetchPokemonSpecies", pokemonNameOrID); err != nil {
+		return pokeapi.PokemonSpecies{}, err
+	}
+
+	species, ok := f.species[pokemonNameOrID]
+	if !ok {
+		return pokeapi.PokemonSpecies{}, notFound("pokemon species", pokemonNameOrID, pokeapi.BaseURL+"/pokemon-species/"+pokemonNameOrID)
+	}
+	return species, nil
+}
+
+func (f *Fake) FetchEvolutionChain(url string) (pokeapi.EvolutionChainResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("FetchEvolutionChain", url); err != nil {
+		return pokeapi.EvolutionChainResponse{}, err
+	}
+
+	if url == "" {
+		return pokeapi.EvolutionChainResponse{}, fmt.Errorf("cannot fetch evolution chain from empty URL")
+	}
+	chain, ok := f.evolutionChains[url]
+	if !ok {
+		return pokeapi.EvolutionChainResponse{}, notFound("evolution chain", url, url)
+	}
+	return chain, nil
+}
+
+func (f *Fake) FetchRegion(regionName string) (pokeapi.Region, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("FetchRegion", regionName); err != nil {
+		return pokeapi.Region{}, err
+	}
+
+	region, ok := f.regions[regionName]
+	if !ok {
+		return pokeapi.Region{}, notFound("region", regionName, pokeapi.BaseURL+"/region/"+regionName)
+	}
+	return region, nil
+}
+
+func (f *Fake) FetchLocation(locationName string) (pokeapi.Location, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("FetchLocation", locationName); err != nil {
+		return pokeapi.Location{}, err
+	}
+
+	location, ok := f.locations[locationName]
+	if !ok {
+		return pokeapi.Location{}, notFound("location", locationName, pokeapi.BaseURL+"/location/"+locationName)
+	}
+	return location, nil
+}
+
+func (f *Fake) FetchMove(moveName string) (pokeapi.Move, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("FetchMove", moveName); err != nil {
+		return pokeapi.Move{}, err
+	}
+
+	move, ok := f.moves[moveName]
+	if !ok {
+		return pokeapi.Move{}, notFound("move", moveName, pokeapi.BaseURL+"/move/"+moveName)
+	}
+	return move, nil
+}
+
+func (f *Fake) FetchPokedex(pokedexName string) (pokeapi.Pokedex, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("FetchPokedex", pokedexName); err != nil {
+		return pokeapi.Pokedex{}, err
+	}
+
+	pokedex, ok := f.pokedexes[pokedexName]
+	if !ok {
+		return pokeapi.Pokedex{}, notFound("pokedex", pokedexName, pokeapi.BaseURL+"/pokedex/"+pokedexName)
+	}
+	return pokedex, nil
+}
+
+func (f *Fake) FetchGeneration(generationName string) (pokeapi.Generation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("FetchGeneration", generationName); err != nil {
+		return pokeapi.Generation{}, err
+	}
+
+	generation, ok := f.generations[generationName]
+	if !ok {
+		return pokeapi.Generation{}, notFound("generation", generationName, pokeapi.BaseURL+"/generation/"+generationName)
+	}
+	return generation, nil
+}
+
+// FetchResourceList serves the registered regions, in the order they were added, for
+// the region list endpoint, honoring the offset and limit of url like PokeAPI does.
+// Other list endpoints are not faked.
+func (f *Fake) FetchResourceList(ctx context.Context, url string) (pokeapi.NamedAPIResourceList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record("FetchResourceList", url); err != nil {
+		return pokeapi.NamedAPIResourceList{}, err
+	}
+
+	path, query, _ := strings.Cut(url, "?")
+	if strings.TrimSuffix(path, "/") != pokeapi.BaseURL+"/region" {
+		return pokeapi.NamedAPIResourceList{}, notFound("resource list", url, url)
+	}
+	params, _ := neturl.ParseQuery(query)
+	offset, _ := strconv.Atoi(params.Get("offset"))
+	limit, err := strconv.Atoi(params.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = pokeapi.DefaultPageLimit
+	}
+
+	list := pokeapi.NamedAPIResourceList{Count: len(f.regionOrder)}
+	for _, name := range f.regionOrder[min(offset, len(f.regionOrder)):min(offset+limit, len(f.regionOrder))] {
+		list.Results = append(list.Results, pokeapi.NamedAPIResource{Name: name, URL: fmt.Sprintf("%s/region/%s/", pokeapi.BaseURL, name)})
+	}
+	if offset+limit < len(f.regionOrder) {
+		next := pokeapi.ListURL("region", offset+limit, limit)
+		list.Next = &next
+	}
+	return list, nil
+}
+
+// All walks the resources of the list endpoint at path like pokeapi.Client.All,
+// following the Next URL of each page FetchResourceList serves. Page options are
+// ignored: the walk always starts at the first resource.
+func (f *Fake) All(ctx context.Context, path string, limit int, opts ...pokeapi.PageOption) iter.Seq2[pokeapi.NamedAPIResource, error] {
+	return func(yield func(pokeapi.NamedAPIResource, error) bool) {
+		url := pokeapi.ListURL(path, 0, limit)
+		for url != "" {
+			page, err := f.FetchResourceList(ctx, url)
+			if err != nil {
+				yield(pokeapi.NamedAPIResource{}, err)
+				return
+			}
+			for _, resource := range page.Results {
+				if !yield(resource, nil) {
+					return
+				}
+			}
+			url = ""
+			if page.Next != nil {
+				url = *page.Next
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package pokeapi
+
+import "fmt"
+
+// NationalPokedex is the name of the Pokedex that numbers every species by national ID.
+const NationalPokedex = "national"
+
+// Pokedex represents data from the /pokedex/{id_or_name}/ endpoint: the national
+// Pokedex, or the regional one of a game, e.g. "kanto" or "original-sinnoh".
+type Pokedex struct {
+	ID             int               `json:"id"`
+	Name           string            `json:"name"`
+	IsMainSeries   bool              `json:"is_main_series"`
+	Region         *NamedAPIResource `json:"region"` // null for the national Pokedex
+	PokemonEntries []PokedexEntry    `json:"pokemon_entries"`
+}
+
+// PokedexEntry is a species and its number in a Pokedex.
+type PokedexEntry struct {
+	EntryNumber    int              `json:"entry_number"`
+	PokemonSpecies NamedAPIResource `json:"pokemon_species"`
+}
+
+// Generation represents data from the /generation/{id_or_name}/ endpoint, with the
+// species introduced in it.
+type Generation struct {
+	ID             int                `json:"id"`
+	Name           string             `json:"name"`
+	MainRegion     NamedAPIResource   `json:"main_region"`
+	PokemonSpecies []NamedAPIResource `json:"pokemon_species"`
+}
+
+// FetchPokedex fetches a Pokedex by name or ID, e.g. NationalPokedex or "kanto".
+func (c *Client) FetchPokedex(pokedexName string) (Pokedex, error) {
+	url := fmt.Sprintf("%s/pokedex/%s", BaseURL, pokedexName)
+
+	return coalesce(c, url, func() (Pokedex, error) {
+		body, err := c.get(url)
+		if err != nil {
+			return Pokedex{}, resourceError("pokedex", pokedexName, err)
+		}
+		return decode[Pokedex](url, body)
+	})
+}
+
+// FetchGeneration fetches a generation by name or ID, e.g. "generation-i".
+func (c *Client) FetchGeneration(generationName string) (Generation, error) {
+	url := fmt.Sprintf("%s/generation/%s", BaseURL, generationName)
+
+	return coalesce(c, url, func() (Generation, error) {
+		body, err := c.get(url)
+		if err != nil {
+			return Generation{}, resourceError("generation", generationName, err)
+		}
+		return decode[Generation](url, body)
+	})
+}
@@ -0,0 +1,53 @@
+package pokeapi
+
+import "fmt"
+
+// Region represents data from the /region/{id_or_name}/ endpoint, e.g. "kanto".
+type Region struct {
+	ID             int                `json:"id"`
+	Name           string             `json:"name"`
+	Locations      []NamedAPIResource `json:"locations"`
+	MainGeneration *NamedAPIResource  `json:"main_generation"` // null for regions without a game of their own
+	Pokedexes      []NamedAPIResource `json:"pokedexes"`       // the region's Pokedexes, the original one first
+}
+
+// Location represents data from the /location/{id_or_name}/ endpoint: a town, route or
+// dungeon, made up of one or more location areas.
+type Location struct {
+	ID     int                `json:"id"`
+	Name   string             `json:"name"`
+	Region *NamedAPIResource  `json:"region"` // null for a few locations outside any region
+	Areas  []NamedAPIResource `json:"areas"`
+}
+
+// RegionName returns the name of the region the location is in, or "" if it has none.
+func (l Location) RegionName() string {
+	if l.Region == nil {
+		return ""
+	}
+	return l.Region.Name
+}
+
+func (c *Client) FetchRegion(regionName string) (Region, error) {
+	url := fmt.Sprintf("%s/region/%s", BaseURL, regionName)
+
+	return coalesce(c, url, func() (Region, error) {
+		body, err := c.get(url)
+		if err != nil {
+			return Region{}, resourceError("region", regionName, err)
+		}
+		return decode[Region](url, body)
+	})
+}
+
+func (c *Client) FetchLocation(locationName string) (Location, error) {
+	url := fmt.Sprintf("%s/location/%s", BaseURL, locationName)
+
+	return coalesce(c, url, func() (Location, error) {
+		body, err := c.get(url)
+		if err != nil {
+			return Location{}, resourceError("location", locationName, err)
+		}
+		return decode[Location](url, body)
+	})
+}
@@ -0,0 +1,114 @@
+package pokeapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/pokecache"
+)
+
+// handlerTransport serves requests straight from an http.Handler, so tests can script
+// PokeAPI behaviour for the real BaseURL without starting a server.
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, req)
+	res := rec.Result()
+	res.Request = req
+	return res, nil
+}
+
+// etagServer serves a fixed Pokemon with validators and answers matching
+// conditional requests with 304 Not Modified.
+type etagServer struct {
+	mu              sync.Mutex
+	sendValidators  bool
+	fullResponses   int
+	notModified     int
+	conditionalReqs int
+}
+
+const (
+	pikachuETag         = `W/"1a2b3c"`
+	pikachuLastModified = "Mon, 19 Oct 2026 10:00:00 GMT"
+)
+
+func (s *etagServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "" {
+		s.conditionalReqs++
+	}
+	if s.sendValidators {
+		if r.Header.Get("If-None-Match") == pikachuETag && r.Header.Get("If-Modified-Since") == pikachuLastModified {
+			s.notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", pikachuETag)
+		w.Header().Set("Last-Modified", pikachuLastModified)
+	}
+	s.fullResponses++
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"id":25,"name":"pikachu","base_experience":112}`))
+}
+
+func TestFetchRevalidatesStaleEntries(t *testing.T) {
+	const cacheInterval = 20 * time.Millisecond
+	server := &etagServer{sendValidators: true}
+	client := pokeapi.NewClient(pokecache.NewCache(cacheInterval), pokeapi.WithTransport(handlerTransport{server}))
+
+	fetch := func() {
+		t.Helper()
+		p, err := client.FetchPokemon("pikachu")
+		if err != nil {
+			t.Fatalf("FetchPokemon returned error: %v", err)
+		}
+		if p.Name != "pikachu" || p.BaseExperience != 112 {
+			t.Fatalf("unexpected Pokemon data: %+v", p)
+		}
+	}
+
+	fetch()
+	fetch() // fresh: served from the cache
+	if server.fullResponses != 1 || server.conditionalReqs != 0 {
+		t.Fatalf("after fresh fetches: full=%d conditional=%d; want 1 and 0", server.fullResponses, server.conditionalReqs)
+	}
+
+	time.Sleep(cacheInterval * 2)
+	fetch() // stale: revalidated with a conditional request
+	if server.notModified != 1 || server.fullResponses != 1 {
+		t.Fatalf("after stale fetch: notModified=%d full=%d; want 1 and 1", server.notModified, server.fullResponses)
+	}
+
+	fetch() // the 304 refreshed the entry's age, so this is a cache hit again
+	if server.conditionalReqs != 1 || server.fullResponses != 1 {
+		t.Errorf("after refreshed fetch: conditional=%d full=%d; want 1 and 1", server.conditionalReqs, server.fullResponses)
+	}
+}
+
+func TestFetchWithoutValidatorsDownloadsAgain(t *testing.T) {
+	const cacheInterval = 20 * time.Millisecond
+	server := &etagServer{sendValidators: false}
+	client := pokeapi.NewClient(pokecache.NewCache(cacheInterval), pokeapi.WithTransport(handlerTransport{server}))
+
+	if _, err := client.FetchPokemon("pikachu"); err != nil {
+		t.Fatalf("FetchPokemon returned error: %v", err)
+	}
+	time.Sleep(cacheInterval * 3)
+	if _, err := client.FetchPokemon("pikachu"); err != nil {
+		t.Fatalf("FetchPokemon returned error: %v", err)
+	}
+
+	if server.fullResponses != 2 || server.conditionalReqs != 0 {
+		t.Errorf("full=%d conditional=%d; want 2 full downloads and no conditional requests", server.fullResponses, server.conditionalReqs)
+	}
+}
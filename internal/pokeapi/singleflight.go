@@ -0,0 +1,132 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// flightGroup deduplicates concurrent calls that share a key: the first caller runs
+// the function and everyone who asks for the same key while it is running waits for,
+// and receives, that one result. It is a trimmed-down golang.org/x/sync/singleflight.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	wg  sync.WaitGroup
+	val any
+	err error
+}
+
+// flightResult is what DoChan delivers.
+type flightResult struct {
+	val    any
+	err    error
+	shared bool
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{calls: make(map[string]*flightCall)}
+}
+
+// Do runs fn once for all concurrent callers using key. shared reports whether the
+// result was handed to more than one caller. A nil group simply calls fn.
+func (g *flightGroup) Do(key string, fn func() (any, error)) (val any, err error, shared bool) {
+	if g == nil {
+		val, err = fn()
+		return val, err, false
+	}
+
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		call.wg.Wait()
+		return call.val, call.err, true
+	}
+	call := &flightCall{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	g.doCall(call, key, fn)
+	return call.val, call.err, false
+}
+
+// doCall runs fn for call. If fn panics, the call is still removed from the group and
+// its waiters are released with an error before the panic carries on up the caller's
+// stack, so a later Do for key runs fn again instead of waiting forever.
+func (g *flightGroup) doCall(call *flightCall, key string, fn func() (any, error)) {
+	returned := false
+	defer func() {
+		var recovered any
+		if !returned {
+			recovered = recover()
+			call.val, call.err = nil, fmt.Errorf("shared request for %s panicked: %v", key, recovered)
+		}
+
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		call.wg.Done()
+
+		if recovered != nil {
+			panic(recovered)
+		}
+	}()
+
+	call.val, call.err = fn()
+	returned = true
+}
+
+// DoChan is like Do, but returns a channel that receives the result once it is ready,
+// so a caller can stop waiting without stopping the call for everyone else.
+func (g *flightGroup) DoChan(key string, fn func() (any, error)) <-chan flightResult {
+	ch := make(chan flightResult, 1)
+	go func() {
+		val, err, shared := g.Do(key, fn)
+		ch <- flightResult{val, err, shared}
+	}()
+	return ch
+}
+
+// coalesce runs fetch through the client's flight group, keyed by the result type and
+// URL, so concurrent requests for the same resource share one HTTP call and one decoded
+// value. Callers get copies of the same struct: slices inside it are shared and must not
+// be modified in place.
+func coalesce[T any](c *Client, url string, fetch func() (T, error)) (T, error) {
+	var zero T
+	key := fmt.Sprintf("%T %s", zero, url)
+
+	val, err, _ := c.flights.Do(key, func() (any, error) {
+		return fetch()
+	})
+	if err != nil {
+		return zero, err
+	}
+	return val.(T), nil
+}
+
+// coalesceContext is coalesce for requests made with a context. The shared request
+// can't be cancelled by any one caller, since others may be waiting for it: it runs
+// with ctx's values but without its cancellation, and each caller stops waiting as
+// soon as its own ctx is done.
+func coalesceContext[T any](ctx context.Context, c *Client, url string, fetch func(context.Context) (T, error)) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	key := fmt.Sprintf("%T %s", zero, url)
+	shared := context.WithoutCancel(ctx)
+
+	select {
+	case res := <-c.flights.DoChan(key, func() (any, error) { return fetch(shared) }):
+		if res.err != nil {
+			return zero, res.err
+		}
+		return res.val.(T), nil
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
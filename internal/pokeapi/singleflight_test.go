@@ -0,0 +1,47 @@
+package pokeapi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFlightGroupRecoversFromAPanic(t *testing.T) {
+	g := newFlightGroup()
+	entered, release := make(chan struct{}), make(chan struct{})
+
+	panicked := make(chan any)
+	go func() {
+		defer func() { panicked <- recover() }()
+		g.Do("key", func() (any, error) {
+			close(entered)
+			<-release
+			panic("boom")
+		})
+	}()
+	<-entered
+
+	waiter := make(chan error)
+	go func() {
+		_, err, _ := g.Do("key", func() (any, error) { return "not shared", nil })
+		waiter <- err
+	}()
+	close(release)
+
+	if r := <-panicked; r != "boom" {
+		t.Errorf("the panic reached the caller as %v; want boom", r)
+	}
+	// The waiter may have joined the panicking call or, if it came late, run its own.
+	if err := <-waiter; err != nil && err.Error() != "shared request for key panicked: boom" {
+		t.Errorf("waiter got %v", err)
+	}
+
+	val, err, shared := g.Do("key", func() (any, error) { return "again", nil })
+	if val != "again" || err != nil || shared {
+		t.Errorf("Do after a panic = %v, %v, %v; want a fresh call", val, err, shared)
+	}
+
+	wantErr := errors.New("failed")
+	if _, err, _ := g.Do("key", func() (any, error) { return nil, wantErr }); err != wantErr {
+		t.Errorf("Do returned %v; want the function's own error", err)
+	}
+}
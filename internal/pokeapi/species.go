@@ -0,0 +1,76 @@
+package pokeapi
+
+import (
+	"strings"
+)
+
+// DefaultLanguage is the language used for flavor text and genus lookups in the REPL.
+const DefaultLanguage = "en"
+
+// PokemonSpecies represents data from the /pokemon-species/{id_or_name}/ endpoint:
+// the Pokedex entry shared by all forms of a species, plus its evolution chain URL.
+type PokemonSpecies struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	EvolutionChain struct {
+		URL string `json:"url"`
+	} `json:"evolution_chain"`
+
+	CaptureRate   int  `json:"capture_rate"`   // 3 (hardest) to 255 (easiest)
+	BaseHappiness *int `json:"base_happiness"` // null for some recent species
+	GenderRate    int  `json:"gender_rate"`    // Chance of being female in eighths, or -1 for genderless
+	IsBaby        bool `json:"is_baby"`
+	IsLegendary   bool `json:"is_legendary"`
+	IsMythical    bool `json:"is_mythical"`
+
+	GrowthRate         NamedAPIResource  `json:"growth_rate"`
+	Generation         NamedAPIResource  `json:"generation"`
+	Habitat            *NamedAPIResource `json:"habitat"` // null for species without a known habitat
+	EvolvesFromSpecies *NamedAPIResource `json:"evolves_from_species"`
+
+	Genera            []Genus           `json:"genera"`
+	FlavorTextEntries []FlavorTextEntry `json:"flavor_text_entries"`
+}
+
+// Genus is the localized "category" of a species, e.g. "Mouse Pokémon".
+type Genus struct {
+	Genus    string           `json:"genus"`
+	Language NamedAPIResource `json:"language"`
+}
+
+// FlavorTextEntry is one localized Pokedex description from a specific game version.
+type FlavorTextEntry struct {
+	FlavorText string           `json:"flavor_text"`
+	Language   NamedAPIResource `json:"language"`
+	Version    NamedAPIResource `json:"version"`
+}
+
+// Genus returns the species' genus in the given language, e.g. "Mouse Pokémon".
+func (s PokemonSpecies) Genus(language string) (string, bool) {
+	for _, g := range s.Genera {
+		if g.Language.Name == language {
+			return g.Genus, true
+		}
+	}
+	return "", false
+}
+
+// FlavorText returns the most recent Pokedex description in the given language,
+// with the line breaks and page breaks of the original game text collapsed into spaces.
+func (s PokemonSpecies) FlavorText(language string) (text string, version string, ok bool) {
+	// PokeAPI lists entries from the oldest game to the newest.
+	for i := len(s.FlavorTextEntries) - 1; i >= 0; i-- {
+		entry := s.FlavorTextEntries[i]
+		if entry.Language.Name == language {
+			return cleanFlavorText(entry.FlavorText), entry.Version.Name, true
+		}
+	}
+	return "", "", false
+}
+
+// cleanFlavorText normalizes the control characters PokeAPI keeps from the game data:
+// form feeds and newlines become spaces, and soft hyphens at line ends are dropped.
+func cleanFlavorText(text string) string {
+	replacer := strings.NewReplacer("\u00ad\n", "", "\u00ad", "", "\f", " ", "\n", " ")
+	return strings.Join(strings.Fields(replacer.Replace(text)), " ")
+}
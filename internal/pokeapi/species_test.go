@@ -0,0 +1,19 @@
+package pokeapi
+
+import "testing"
+
+func TestFlavorTextPicksLatestEntryAndCleansIt(t *testing.T) {
+	s := PokemonSpecies{FlavorTextEntries: []FlavorTextEntry{
+		{FlavorText: "Old\nentry.", Language: NamedAPIResource{Name: "en"}, Version: NamedAPIResource{Name: "red"}},
+		{FlavorText: "It stores elec­\ntricity\fin its\ncheeks.", Language: NamedAPIResource{Name: "en"}, Version: NamedAPIResource{Name: "sword"}},
+		{FlavorText: "ほっぺたに 電気を ためる。", Language: NamedAPIResource{Name: "ja"}, Version: NamedAPIResource{Name: "shield"}},
+	}}
+
+	text, version, ok := s.FlavorText("en")
+	if !ok || version != "sword" {
+		t.Fatalf("FlavorText(en) = %q from %s, %v; want the sword entry", text, version, ok)
+	}
+	if want := "It stores electricity in its cheeks."; text != want {
+		t.Errorf("FlavorText(en) = %q; want %q", text, want)
+	}
+}
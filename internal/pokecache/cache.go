@@ -1,50 +1,129 @@
 package pokecache
 
 import (
+	"log/slog"
 	"sync"
 	"time"
+
+	"github.com/voidarchive/pokedex/internal/shared/logging"
 )
 
+// staleRetention is how many reap intervals an entry with validators is kept after it
+// goes stale. Those entries can still be revalidated with a conditional request, which
+// is much cheaper than downloading the body again.
+const staleRetention = 12
+
+// Validators are the HTTP response headers needed to revalidate a cached body
+// with If-None-Match / If-Modified-Since.
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// IsZero reports whether there is nothing to revalidate with.
+func (v Validators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
 type cacheEntry struct {
-	createdAt time.Time
-	val       []byte
+	createdAt  time.Time
+	val        []byte
+	validators Validators
 }
 
 type Cache struct {
 	entries      map[string]cacheEntry
 	mu           sync.Mutex
 	reapInterval time.Duration
+	logger       *slog.Logger
 }
 
-func NewCache(interval time.Duration) *Cache {
+// Option customizes a Cache created by NewCache.
+type Option func(*Cache)
+
+// WithLogger makes the cache log additions, refreshes and reaps at debug level.
+// Without it the cache is silent.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Cache) {
+		c.logger = logger
+	}
+}
+
+func NewCache(interval time.Duration, opts ...Option) *Cache {
 	c := &Cache{
 		entries:      make(map[string]cacheEntry),
 		reapInterval: interval,
+		logger:       logging.Discard(),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
 	go c.reapLoop()
 	return c
 }
 
 func (c *Cache) Add(key string, val []byte) {
+	c.AddWithValidators(key, val, Validators{})
+}
+
+// AddWithValidators stores val together with the validators it was served with.
+// Unlike plain entries, these outlive the reap interval as stale entries (see GetStale).
+func (c *Cache) AddWithValidators(key string, val []byte, validators Validators) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.entries[key] = cacheEntry{
-		time.Now().UTC(),
-		val,
+		createdAt:  time.Now().UTC(),
+		val:        val,
+		validators: validators,
 	}
+	c.logger.Debug("cache add", "key", key, "bytes", len(val), "etag", validators.ETag, "last_modified", validators.LastModified)
 }
 
+// Get returns the value for key if it is still fresh, i.e. younger than the reap interval.
 func (c *Cache) Get(key string) ([]byte, bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	entry, ok := c.entries[key]
-	if !ok {
+	if !ok || c.isStale(entry) {
 		return nil, false
 	}
 	return entry.val, true
 }
 
+// GetStale returns the value for key along with its validators, regardless of its age.
+// Only entries that have validators are returned, since nothing else can be revalidated.
+func (c *Cache) GetStale(key string) ([]byte, Validators, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || entry.validators.IsZero() {
+		return nil, Validators{}, false
+	}
+	return entry.val, entry.validators, true
+}
+
+// Refresh resets the age of the entry for key, typically after the server answered a
+// conditional request with 304 Not Modified. It reports whether the entry existed.
+func (c *Cache) Refresh(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	entry.createdAt = time.Now().UTC()
+	c.entries[key] = entry
+	c.logger.Debug("cache refresh", "key", key)
+	return true
+}
+
+func (c *Cache) isStale(entry cacheEntry) bool {
+	return entry.createdAt.Before(time.Now().UTC().Add(-c.reapInterval))
+}
+
 func (c *Cache) reapLoop() {
 	ticker := time.NewTicker(c.reapInterval)
 	defer ticker.Stop()
@@ -59,11 +138,21 @@ func (c *Cache) reap() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	expirationTime := time.Now().UTC().Add(-c.reapInterval)
+	now := time.Now().UTC()
+	expirationTime := now.Add(-c.reapInterval)
+	staleExpirationTime := now.Add(-c.reapInterval * staleRetention)
 
+	reaped := 0
 	for key, entry := range c.entries {
-		if entry.createdAt.Before(expirationTime) {
+		if entry.validators.IsZero() && entry.createdAt.Before(expirationTime) {
 			delete(c.entries, key)
+			reaped++
+		} else if entry.createdAt.Before(staleExpirationTime) {
+			delete(c.entries, key)
+			reaped++
 		}
 	}
+	if reaped > 0 {
+		c.logger.Debug("cache reap", "reaped", reaped, "remaining", len(c.entries))
+	}
 }
@@ -1,6 +1,9 @@
 package pokecache
 
 import (
+	"bytes"
+	"log/slog"
+	"strings"
 	"testing"
 	"time"
 )
@@ -81,3 +84,71 @@ func TestCacheReapLoop(t *testing.T) {
 		t.Errorf("expected key %s to be reaped after interval, but it was found", key)
 	}
 }
+
+func TestCacheValidatedEntriesGoStaleInsteadOfBeingReaped(t *testing.T) {
+	const reapInterval = 50 * time.Millisecond
+	cache := NewCache(reapInterval)
+
+	key := "https://pokeapi.co/api/v2/pokemon/pikachu"
+	val := []byte(`{"name":"pikachu"}`)
+	validators := Validators{ETag: `W/"abc"`, LastModified: "Mon, 19 Oct 2026 10:00:00 GMT"}
+	cache.AddWithValidators(key, val, validators)
+	cache.Add("plainKey", []byte("plainValue"))
+
+	if _, ok := cache.Get(key); !ok {
+		t.Fatalf("expected fresh entry %s to be returned by Get", key)
+	}
+
+	time.Sleep(reapInterval * 3)
+
+	if _, ok := cache.Get(key); ok {
+		t.Errorf("expected stale entry %s not to be returned by Get", key)
+	}
+	if _, _, ok := cache.GetStale("plainKey"); ok {
+		t.Errorf("expected entry without validators to be reaped")
+	}
+
+	staleVal, staleValidators, ok := cache.GetStale(key)
+	if !ok {
+		t.Fatalf("expected stale entry %s to be kept for revalidation", key)
+	}
+	if string(staleVal) != string(val) || staleValidators != validators {
+		t.Errorf("GetStale = %s, %+v; want %s, %+v", staleVal, staleValidators, val, validators)
+	}
+
+	if !cache.Refresh(key) {
+		t.Fatalf("expected Refresh to find %s", key)
+	}
+	if _, ok := cache.Get(key); !ok {
+		t.Errorf("expected refreshed entry %s to be fresh again", key)
+	}
+	if cache.Refresh("missingKey") {
+		t.Errorf("expected Refresh of a missing key to report false")
+	}
+}
+
+func TestCacheStaleEntriesAreEventuallyReaped(t *testing.T) {
+	const reapInterval = 5 * time.Millisecond
+	cache := NewCache(reapInterval)
+
+	key := "etagKey"
+	cache.AddWithValidators(key, []byte("val"), Validators{ETag: `"v1"`})
+
+	time.Sleep(reapInterval * (staleRetention + 4))
+
+	if _, _, ok := cache.GetStale(key); ok {
+		t.Errorf("expected %s to be reaped after the stale retention period", key)
+	}
+}
+
+func TestCacheLogsWithInjectedLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	cache := NewCache(5*time.Second, WithLogger(logger))
+
+	cache.Add("loggedKey", []byte("loggedValue"))
+
+	if !strings.Contains(buf.String(), "cache add") || !strings.Contains(buf.String(), "key=loggedKey") {
+		t.Errorf("expected an add record for loggedKey, got %q", buf.String())
+	}
+}
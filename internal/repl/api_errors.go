@@ -0,0 +1,44 @@
+package repl
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/shared/constants"
+)
+
+// hintError is a player-facing message that still wraps the original client error,
+// so callers can keep using errors.Is / errors.As on it.
+type hintError struct {
+	msg string
+	err error
+}
+
+func (e *hintError) Error() string { return e.msg }
+func (e *hintError) Unwrap() error { return e.err }
+
+// explainAPIError turns a PokeapiClient error into a message the player can act on.
+// notFound is shown when the requested resource doesn't exist; anything that isn't a
+// known client error is returned unchanged.
+func explainAPIError(err error, notFound string) error {
+	var msg string
+	var httpErr *pokeapi.HTTPError
+	var decodeErr *pokeapi.DecodeError
+
+	switch {
+	case errors.Is(err, pokeapi.ErrNotFound):
+		msg = fmt.Sprintf("%s%s%s", constants.ColorYellow, notFound, constants.ColorReset)
+	case errors.Is(err, pokeapi.ErrRateLimited):
+		msg = fmt.Sprintf("%sPokeAPI is rate limiting requests right now. Wait a minute and try again.%s", constants.ColorYellow, constants.ColorReset)
+	case errors.Is(err, pokeapi.ErrNetwork):
+		msg = fmt.Sprintf("%scould not reach PokeAPI. Check your internet connection and try again.%s", constants.ColorRed, constants.ColorReset)
+	case errors.As(err, &decodeErr):
+		msg = fmt.Sprintf("%sPokeAPI sent a response that couldn't be read (%s). Try again later.%s", constants.ColorRed, decodeErr.URL, constants.ColorReset)
+	case errors.As(err, &httpErr):
+		msg = fmt.Sprintf("%sPokeAPI is having trouble (status %s%d%s). Try again later.%s", constants.ColorRed, constants.ColorBrightRed, httpErr.Status, constants.ColorRed, constants.ColorReset)
+	default:
+		return err
+	}
+	return &hintError{msg: msg, err: err}
+}
@@ -0,0 +1,91 @@
+package repl
+
+import "time"
+
+// timeOfDay returns "morning" (04:00-10:00), "day" (10:00-20:00) or "night", the
+// periods PokeAPI uses for time-based encounters and evolutions.
+func timeOfDay(t time.Time) string {
+	switch h := t.Hour(); {
+	case h >= 4 && h < 10:
+		return "morning"
+	case h >= 10 && h < 20:
+		return "day"
+	default:
+		return "night"
+	}
+}
+
+// season returns the season of t for encounters that change with it.
+func season(t time.Time) string {
+	switch t.Month() {
+	case time.March, time.April, time.May:
+		return "spring"
+	case time.June, time.July, time.August:
+		return "summer"
+	case time.September, time.October, time.November:
+		return "autumn"
+	default:
+		return "winter"
+	}
+}
+
+// DefaultClockStep is how far a simulated clock moves with every action.
+const DefaultClockStep = 10 * time.Minute
+
+// GameClock is the in-game clock. It either follows the wall clock, or is simulated:
+// then it starts at a set time and moves forward by Step with every action the player
+// takes, such as exploring or traveling.
+type GameClock struct {
+	Step time.Duration
+
+	simulated bool
+	current   time.Time
+}
+
+// NewWallClock returns a clock that always shows the computer's time.
+func NewWallClock() *GameClock {
+	return &GameClock{Step: DefaultClockStep}
+}
+
+// NewSimulatedClock returns a clock that starts at start and only moves with actions.
+func NewSimulatedClock(start time.Time) *GameClock {
+	return &GameClock{Step: DefaultClockStep, simulated: true, current: start}
+}
+
+// Now returns the in-game time.
+func (c *GameClock) Now() time.Time {
+	if !c.simulated {
+		return time.Now()
+	}
+	return c.current
+}
+
+// Simulated reports whether the clock only moves with actions.
+func (c *GameClock) Simulated() bool {
+	return c.simulated
+}
+
+// Advance moves a simulated clock forward by one Step per action. A wall clock
+// moves on its own, so it is left alone.
+func (c *GameClock) Advance(actions int) {
+	if c.simulated {
+		c.current = c.current.Add(time.Duration(actions) * c.Step)
+	}
+}
+
+// now returns the current time according to cfg's clock.
+func (cfg *Config) now() time.Time {
+	if cfg.Clock == nil {
+		return time.Now()
+	}
+	return cfg.Clock.Now()
+}
+
+// evolutionTimeOfDay returns the time of day the way evolution details name it:
+// PokeAPI's evolutions only tell "day" from "night", and morning counts as day.
+func evolutionTimeOfDay(t time.Time) string {
+	if timeOfDay(t) == "night" {
+		return "night"
+	}
+	return "day"
+}
@@ -0,0 +1,48 @@
+package repl
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSimulatedClockMovesWithActions(t *testing.T) {
+	start := time.Date(2024, time.May, 1, 19, 55, 0, 0, time.UTC)
+	cfg := NewConfig(nil, nil, nil)
+	cfg.Clock = NewSimulatedClock(start)
+
+	if err := ExecuteLine(cfg, "help"); err != nil {
+		t.Fatalf("help returned error: %v", err)
+	}
+	if got := cfg.now(); !got.Equal(start) {
+		t.Errorf("help is not an action, but the clock moved to %s", got.Format("15:04"))
+	}
+	if err := ExecuteLine(cfg, "run"); err == nil {
+		t.Fatalf("expected run to fail outside an encounter")
+	}
+	if got := cfg.now(); !got.Equal(start) {
+		t.Errorf("a failed action should not take time, but the clock moved to %s", got.Format("15:04"))
+	}
+
+	cfg.Clock.Advance(1)
+	if got := cfg.now().Format("15:04"); got != "20:05" {
+		t.Errorf("clock after one action = %s; want 20:05", got)
+	}
+	if !strings.Contains(prompt(cfg), "[20:05 night]") {
+		t.Errorf("prompt %q should show the time", prompt(cfg))
+	}
+
+	wall := NewWallClock()
+	wall.Advance(10)
+	if time.Since(wall.Now()) > time.Minute {
+		t.Errorf("a wall clock should not be moved by actions")
+	}
+}
+
+func TestEvolutionTimeOfDay(t *testing.T) {
+	for hour, want := range map[int]string{3: "night", 4: "day", 12: "day", 19: "day", 20: "night"} {
+		if got := evolutionTimeOfDay(time.Date(2024, time.May, 1, hour, 0, 0, 0, time.UTC)); got != want {
+			t.Errorf("evolutionTimeOfDay(%02d:00) = %s; want %s", hour, got, want)
+		}
+	}
+}
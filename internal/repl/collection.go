@@ -0,0 +1,211 @@
+package repl
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/shared/constants"
+)
+
+// maxNicknameLength is how long a nickname can be, as in the games.
+const maxNicknameLength = 12
+
+// ownedPokemon returns every Pokemon the player owns, ordered by ID.
+func ownedPokemon(cfg *Config) []pokeapi.UserPokemon {
+	owned := make([]pokeapi.UserPokemon, 0, len(cfg.Collection))
+	for _, p := range cfg.Collection {
+		owned = append(owned, p)
+	}
+	slices.SortFunc(owned, func(a, b pokeapi.UserPokemon) int { return a.UID - b.UID })
+	return owned
+}
+
+// addOwnedPokemon gives a newly caught Pokemon its ID and adds it to the collection,
+// and to the party if there's room or else to the PC. It returns the stored Pokemon
+// and whether it joined the party. Check roomForPokemon first.
+func addOwnedPokemon(cfg *Config, p pokeapi.UserPokemon) (pokeapi.UserPokemon, bool) {
+	p.UID = cfg.newUID()
+	cfg.Collection[p.UID] = p
+	if len(cfg.Party) >= MaxPartySize {
+		storeInPC(cfg, p.UID)
+		return p, false
+	}
+	cfg.Party = append(cfg.Party, p.UID)
+	return p, true
+}
+
+// storeOwnedPokemon writes a changed Pokemon back to the collection. The party and the
+// boxes refer to it by UID, so they see the change too.
+func storeOwnedPokemon(cfg *Config, pokemon pokeapi.UserPokemon) {
+	cfg.Collection[pokemon.UID] = pokemon
+}
+
+// partyPokemon returns the party members in slot order.
+func partyPokemon(cfg *Config) []pokeapi.UserPokemon {
+	party := make([]pokeapi.UserPokemon, len(cfg.Party))
+	for i, uid := range cfg.Party {
+		party[i] = cfg.Collection[uid]
+	}
+	return party
+}
+
+// swapPartySlots swaps the party members in slots i and j, counted from 0.
+func swapPartySlots(cfg *Config, i, j int) {
+	cfg.Party[i], cfg.Party[j] = cfg.Party[j], cfg.Party[i]
+}
+
+// movePartySlot moves the party member in slot from to slot to, counted from 0; the
+// ones in between shift over.
+func movePartySlot(cfg *Config, from, to int) {
+	uid := cfg.Party[from]
+	cfg.Party = slices.Insert(slices.Delete(cfg.Party, from, from+1), to, uid)
+}
+
+// removeFromParty takes an owned Pokemon out of the party, if it's in it.
+func removeFromParty(cfg *Config, uid int) {
+	cfg.Party = slices.DeleteFunc(cfg.Party, func(id int) bool { return id == uid })
+}
+
+// releasePokemon lets an owned Pokemon go: it leaves the collection, and the party or
+// its box. The Pokedex still counts its species as caught.
+func releasePokemon(cfg *Config, uid int) {
+	removeFromParty(cfg, uid)
+	takeFromPC(cfg, uid)
+	delete(cfg.Collection, uid)
+}
+
+// matchPokemon returns the owned Pokemon ref can mean: the one with that ID ("3" or
+// "#3"), else those with that nickname, else those of that species.
+func matchPokemon(cfg *Config, ref string) []pokeapi.UserPokemon {
+	if uid, err := strconv.Atoi(strings.TrimPrefix(ref, "#")); err == nil {
+		if p, ok := cfg.Collection[uid]; ok {
+			return []pokeapi.UserPokemon{p}
+		}
+		return nil
+	}
+
+	var byNickname, bySpecies []pokeapi.UserPokemon
+	for _, p := range ownedPokemon(cfg) {
+		switch {
+		case p.Nickname != "" && strings.EqualFold(p.Nickname, ref):
+			byNickname = append(byNickname, p)
+		case p.Name == ref:
+			bySpecies = append(bySpecies, p)
+		}
+	}
+	if len(byNickname) > 0 {
+		return byNickname
+	}
+	return bySpecies
+}
+
+// resolvePokemon finds the one owned Pokemon ref refers to, by ID, nickname or species.
+// If ref fits several, the error lists them so the player can pick one by ID.
+func resolvePokemon(cfg *Config, ref string) (pokeapi.UserPokemon, error) {
+	matches := matchPokemon(cfg, ref)
+	switch len(matches) {
+	case 0:
+		return pokeapi.UserPokemon{}, errNotOwned(ref)
+	case 1:
+		return matches[0], nil
+	}
+
+	labels := make([]string, len(matches))
+	for i, p := range matches {
+		labels[i] = fmt.Sprintf("%s (Lvl %d)", pokemonLabel(p), p.Level)
+	}
+	return pokeapi.UserPokemon{}, fmt.Errorf("%syou have %d Pokemon called %s%s%s: %s. Use the ID to pick one, e.g. '#%d'%s",
+		constants.ColorYellow, len(matches), constants.ColorBrightYellow, ref, constants.ColorYellow, strings.Join(labels, ", "), matches[0].UID, constants.ColorReset)
+}
+
+// resolvePartyPokemon finds the party member ref refers to, for the player to action it,
+// e.g. "battle with" or "trade". Pokemon in the PC have to be withdrawn first. A
+// species name picks its one party member even if more of that species are boxed.
+func resolvePartyPokemon(cfg *Config, ref, action string) (pokeapi.UserPokemon, error) {
+	matches := matchPokemon(cfg, ref)
+	if len(matches) == 0 {
+		return pokeapi.UserPokemon{}, fmt.Errorf("%syou have not caught '%s%s%s' to %s%s", constants.ColorYellow, constants.ColorBrightRed, ref, constants.ColorYellow, action, constants.ColorReset)
+	}
+	inParty := slices.DeleteFunc(slices.Clone(matches), func(p pokeapi.UserPokemon) bool { return partySlot(cfg, p.UID) < 0 })
+	switch len(inParty) {
+	case 0:
+		p := matches[0]
+		return pokeapi.UserPokemon{}, fmt.Errorf("%s%s is in %s, not your party. Take it out with 'withdraw %s' to %s it%s", constants.ColorYellow, pokemonLabel(p), whereIs(cfg, p.UID), pokemonRef(p), action, constants.ColorReset)
+	case 1:
+		return inParty[0], nil
+	}
+	return resolvePokemon(cfg, ref)
+}
+
+func errNotOwned(ref string) error {
+	return fmt.Errorf("%syou have not caught %s%s%s%s", constants.ColorYellow, constants.ColorBrightRed, ref, constants.ColorYellow, constants.ColorReset)
+}
+
+// pokemonLabel names an owned Pokemon in lists: its ID, nickname and species, e.g.
+// "#3 buzz (butterfree)" or "#4 pikachu".
+func pokemonLabel(p pokeapi.UserPokemon) string {
+	if p.Nickname == "" {
+		return fmt.Sprintf("#%d %s", p.UID, p.Name)
+	}
+	return fmt.Sprintf("#%d %s (%s)", p.UID, p.Nickname, p.Name)
+}
+
+// commandNickname gives an owned Pokemon a nickname, or removes it when none is given.
+func commandNickname(cfg *Config, args ...string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("%susage: nickname <pokemon> [nickname]%s", constants.ColorYellow, constants.ColorReset)
+	}
+	pokemon, err := resolvePokemon(cfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		if pokemon.Nickname == "" {
+			return fmt.Errorf("%s%s doesn't have a nickname%s", constants.ColorYellow, pokemonLabel(pokemon), constants.ColorReset)
+		}
+		nickname := pokemon.Nickname
+		pokemon.Nickname = ""
+		storeOwnedPokemon(cfg, pokemon)
+		fmt.Printf("%s%s%s is called %s%s%s again.%s\n", constants.ColorYellow, nickname, constants.ColorCyan, constants.ColorYellow, pokemon.Name, constants.ColorCyan, constants.ColorReset)
+		return nil
+	}
+
+	nickname := args[1]
+	if len([]rune(nickname)) > maxNicknameLength {
+		return fmt.Errorf("%sa nickname can be at most %d characters long%s", constants.ColorYellow, maxNicknameLength, constants.ColorReset)
+	}
+	if _, err := strconv.Atoi(strings.TrimPrefix(nickname, "#")); err == nil {
+		return fmt.Errorf("%sa nickname can't be a number, it would look like an ID%s", constants.ColorYellow, constants.ColorReset)
+	}
+	pokemon.Nickname = nickname
+	storeOwnedPokemon(cfg, pokemon)
+	fmt.Printf("%s%s%s is now called %s%s%s.%s\n", constants.ColorYellow, pokemon.Name, constants.ColorCyan, constants.ColorBrightYellow, nickname, constants.ColorCyan, constants.ColorReset)
+	return nil
+}
+
+// commandRelease lets one of the player's Pokemon go for good, once they confirm.
+// The last Pokemon of the party can't be released.
+func commandRelease(cfg *Config, args ...string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%susage: release <pokemon>%s", constants.ColorYellow, constants.ColorReset)
+	}
+	pokemon, err := resolvePokemon(cfg, args[0])
+	if err != nil {
+		return err
+	}
+	if partySlot(cfg, pokemon.UID) >= 0 && len(cfg.Party) == 1 {
+		return fmt.Errorf("%s%s is the last Pokemon in your party: you can't release it%s", constants.ColorYellow, pokemon.DisplayName(), constants.ColorReset)
+	}
+
+	if !confirm(cfg, fmt.Sprintf("Release %s (Lvl %d)? You won't get it back.", pokemonLabel(pokemon), pokemon.Level), false) {
+		fmt.Printf("%s%s stays with you.%s\n", constants.ColorGray, pokemon.DisplayName(), constants.ColorReset)
+		return nil
+	}
+	releasePokemon(cfg, pokemon.UID)
+	fmt.Printf("%s%s%s was released. Bye-bye, %s!%s\n", constants.ColorYellow, pokemon.DisplayName(), constants.ColorCyan, pokemon.DisplayName(), constants.ColorReset)
+	return nil
+}
@@ -0,0 +1,173 @@
+package repl
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/pokeapi/pokeapitest"
+)
+
+func newCollectionConfig(t *testing.T) *Config {
+	t.Helper()
+	stats := pokeapitest.Stats{HP: 35, Attack: 55, Defense: 40, SpecialAttack: 50, SpecialDefense: 50, Speed: 90}
+	fake := pokeapitest.NewFake().AddPokemon(
+		pokeapitest.NewPokemon(25, "pikachu", 112, stats, "electric"),
+		pokeapitest.NewPokemon(133, "eevee", 65, stats, "normal"),
+	)
+	cfg := NewConfig(fake, nil, rand.New(rand.NewSource(1)))
+	for _, name := range []string{"pikachu", "pikachu", "eevee"} {
+		data, err := fake.FetchPokemon(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addOwnedPokemon(cfg, pokeapi.NewUserPokemon(data, 5))
+	}
+	return cfg
+}
+
+func TestResolvePokemon(t *testing.T) {
+	cfg := newCollectionConfig(t)
+	if err := commandNickname(cfg, "2", "sparky"); err != nil {
+		t.Fatalf("nickname: %v", err)
+	}
+
+	tests := []struct {
+		ref     string
+		wantUID int
+		wantErr string
+	}{
+		{ref: "1", wantUID: 1},
+		{ref: "#2", wantUID: 2},
+		{ref: "sparky", wantUID: 2},
+		{ref: "eevee", wantUID: 3},
+		{ref: "pikachu", wantErr: "#1 pikachu (Lvl 5), #2 sparky (pikachu) (Lvl 5). Use the ID"},
+		{ref: "#9", wantErr: "you have not caught"},
+		{ref: "mew", wantErr: "you have not caught"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.ref, func(t *testing.T) {
+			got, err := resolvePokemon(cfg, tc.ref)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("resolvePokemon(%q) error = %v; want it to contain %q", tc.ref, err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolvePokemon(%q) returned error: %v", tc.ref, err)
+			}
+			if got.UID != tc.wantUID {
+				t.Errorf("resolvePokemon(%q) = #%d; want #%d", tc.ref, got.UID, tc.wantUID)
+			}
+		})
+	}
+}
+
+func TestCommandNickname(t *testing.T) {
+	cfg := newCollectionConfig(t)
+
+	if err := commandNickname(cfg, "eevee", "fluffy"); err != nil {
+		t.Fatalf("nickname: %v", err)
+	}
+	if cfg.Collection[3].Nickname != "fluffy" || partyPokemon(cfg)[2].Nickname != "fluffy" {
+		t.Errorf("nickname should be stored in the collection and show in the party, got %q and %q", cfg.Collection[3].Nickname, partyPokemon(cfg)[2].Nickname)
+	}
+	if got := cfg.Collection[3].DisplayName(); got != "fluffy" {
+		t.Errorf("DisplayName = %q; want fluffy", got)
+	}
+
+	for _, bad := range []string{"42", "#7", "averyverylongname"} {
+		if err := commandNickname(cfg, "fluffy", bad); err == nil {
+			t.Errorf("expected nickname %q to be rejected", bad)
+		}
+	}
+
+	if err := commandNickname(cfg, "fluffy"); err != nil {
+		t.Fatalf("clearing the nickname: %v", err)
+	}
+	if got := cfg.Collection[3]; got.Nickname != "" || got.DisplayName() != "eevee" {
+		t.Errorf("nickname should be cleared, got %q", got.Nickname)
+	}
+	if err := commandNickname(cfg, "eevee"); err == nil {
+		t.Errorf("expected an error clearing a nickname that isn't set")
+	}
+}
+
+func TestCommandParty(t *testing.T) {
+	cfg := newCollectionConfig(t)
+
+	steps := []struct {
+		args []string
+		want []int
+	}{
+		{[]string{"swap", "1", "3"}, []int{3, 2, 1}},
+		{[]string{"move", "1", "3"}, []int{2, 1, 3}},
+		{[]string{"lead", "3"}, []int{3, 2, 1}},
+		{[]string{"remove", "2"}, []int{3, 1}},
+	}
+	for _, step := range steps {
+		if err := commandParty(cfg, step.args...); err != nil {
+			t.Fatalf("party %v: %v", step.args, err)
+		}
+		if got := cfg.Party; !slices.Equal(got, step.want) {
+			t.Fatalf("after party %v the party is %v; want %v", step.args, got, step.want)
+		}
+	}
+	if boxOf(cfg, 2) != 0 {
+		t.Errorf("#2 should have gone to Box 1 when removed from the party")
+	}
+
+	for _, args := range [][]string{{"swap", "1", "3"}, {"lead", "0"}, {"move", "x", "1"}, {"remove"}, {"dance", "1"}} {
+		if err := commandParty(cfg, args...); err == nil {
+			t.Errorf("party %v should fail", args)
+		}
+	}
+	if err := commandParty(cfg, "remove", "1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := commandParty(cfg, "remove", "1"); err == nil || !strings.Contains(err.Error(), "last Pokemon in your party") {
+		t.Errorf("removing the last party member: error = %v; want it refused", err)
+	}
+}
+
+func TestCommandRelease(t *testing.T) {
+	cfg := newCollectionConfig(t)
+	cfg.Dex.markCaught("eevee")
+	if err := commandDeposit(cfg, "eevee"); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.Input = &answers{"n"}
+	if err := commandRelease(cfg, "eevee"); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if _, owned := cfg.Collection[3]; !owned {
+		t.Fatal("answering no should keep eevee")
+	}
+
+	cfg.Input = &answers{"y"}
+	if err := commandRelease(cfg, "eevee"); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if _, owned := cfg.Collection[3]; owned || boxOf(cfg, 3) >= 0 {
+		t.Errorf("eevee should be gone from the collection and its box, collection = %v, boxes = %v", cfg.Collection, cfg.Boxes[0])
+	}
+	if !cfg.Dex.Caught["eevee"] {
+		t.Error("releasing a Pokemon shouldn't take its species off the Pokedex")
+	}
+
+	cfg.Input = &answers{"y"}
+	if err := commandRelease(cfg, "#1"); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if got := cfg.Party; !slices.Equal(got, []int{2}) {
+		t.Errorf("party = %v; want only #2 left", got)
+	}
+	if err := commandRelease(cfg, "#2"); err == nil || !strings.Contains(err.Error(), "last Pokemon in your party") {
+		t.Errorf("releasing the last party member: error = %v; want it refused", err)
+	}
+}
@@ -4,66 +4,145 @@ import (
 	"fmt"
 
 	"github.com/voidarchive/pokedex/internal/battle"
+	"github.com/voidarchive/pokedex/internal/pokeapi"
 	"github.com/voidarchive/pokedex/internal/shared/constants"
 )
 
 // commandBattle handles the 'battle' command from the REPL.
-// It initiates a battle between a user's caught Pokemon and a specified opponent Pokemon.
+// With one name it fights a round against the wild Pokemon of the current encounter,
+// and with none it sends out the party's lead; with two it simulates a full battle
+// between a caught Pokemon and an opponent.
 func commandBattle(cfg *Config, args ...string) error {
+	if len(args) == 0 && cfg.Encounter != nil {
+		lead, err := leadBattler(cfg)
+		if err != nil {
+			return err
+		}
+		return battleWild(cfg, pokemonRef(lead))
+	}
+	if len(args) == 1 {
+		return battleWild(cfg, args[0])
+	}
 	if len(args) < 2 {
-		return fmt.Errorf("%susage: battle <your_pokemon_name> <opponent_pokemon_name>%s", constants.ColorYellow, constants.ColorReset)
+		return fmt.Errorf("%susage: battle [your_pokemon_name] [opponent_pokemon_name]%s", constants.ColorYellow, constants.ColorReset)
 	}
-	playerPokemonName := args[0]
 	opponentPokemonName := args[1]
 
-	playerPokemon, caught := cfg.Pokedex[playerPokemonName]
-	if !caught {
-		return fmt.Errorf("%syou have not caught '%s%s%s' to battle with%s", constants.ColorYellow, constants.ColorBrightRed, playerPokemonName, constants.ColorYellow, constants.ColorReset)
+	playerPokemon, err := resolvePartyPokemon(cfg, args[0], "battle with")
+	if err != nil {
+		return err
 	}
 
 	fmt.Printf("%sFetching opponent %s%s%s for battle...%s\n", constants.ColorCyan, constants.ColorYellow, opponentPokemonName, constants.ColorCyan, constants.ColorReset)
 	opponentPokemonData, err := cfg.PokeapiClient.FetchPokemon(opponentPokemonName)
 	if err != nil {
-		// Error from FetchPokemon is already descriptive, but we can color the wrapper message
-		return fmt.Errorf("%scould not fetch opponent Pokemon '%s%s%s': %w%s", constants.ColorRed, constants.ColorYellow, opponentPokemonName, constants.ColorRed, err, constants.ColorReset)
+		return explainAPIError(err, fmt.Sprintf("there's no Pokemon called '%s%s%s' to battle. Check the spelling: names are lowercase with dashes, e.g. 'mr-mime'", constants.ColorBrightRed, opponentPokemonName, constants.ColorYellow))
 	}
 
+	cfg.Dex.markSeen(opponentPokemonData.SpeciesName())
+
 	// SimulateBattle now returns xpGained
 	xpGained := battle.SimulateBattle(cfg.Randomizer, playerPokemon, opponentPokemonData)
 
 	if xpGained > 0 {
-		// Get the pokemon from Pokedex to update (it's a struct, so we operate on a copy then reassign)
-		updatedPlayerPokemon := cfg.Pokedex[playerPokemonName] // Get a fresh copy
-		leveledUp := updatedPlayerPokemon.AddXP(xpGained)      // AddXP modifies updatedPlayerPokemon directly
-		cfg.Pokedex[playerPokemonName] = updatedPlayerPokemon  // Re-assign the modified Pokemon to the Pokedex
-
-		// Update in party if present
-		for i, p := range cfg.Party {
-			if p.Name == playerPokemonName {
-				cfg.Party[i] = updatedPlayerPokemon // Update the Pokemon in the party slot
-				break
-			}
-		}
+		changeHappiness(cfg, playerPokemon.UID, pokeapi.HappinessVictory)
+		awardXP(cfg, playerPokemon.UID, xpGained)
+	} else {
+		changeHappiness(cfg, playerPokemon.UID, pokeapi.HappinessFaint)
+	}
 
-		if leveledUp {
-			// The AddXP method already prints level up messages.
-			// We could add more post-level up logic here if needed.
-			fmt.Printf("%s%s%s's stats may have changed due to leveling up!%s\n", constants.ColorGreen, constants.ColorYellow, playerPokemonName, constants.ColorReset)
-
-			// Check for evolution after leveling up
-			evolved, err := CheckAndHandleEvolution(cfg, updatedPlayerPokemon.Name) // Pass the name of the (potentially) updated Pokemon
-			if err != nil {
-				// CheckAndHandleEvolution and performEvolution already color their errors, this is a fallback/wrapper
-				fmt.Printf("%sError during evolution check for %s%s%s: %v%s\n", constants.ColorRed, constants.ColorYellow, updatedPlayerPokemon.Name, constants.ColorRed, err, constants.ColorReset)
-			}
-			if evolved {
-				// If evolution occurred, updatedPlayerPokemon is now stale. cfg.Pokedex and cfg.Party have the new Pokemon.
-				// The evolution messages are handled by CheckAndHandleEvolution.
-				// We might need to refetch the evolved Pokemon if we need its new name here, but for now, not necessary.
-				fmt.Printf("%s--- %s%s%s has evolved! ---%s\n", constants.ColorBrightPurple, constants.ColorYellow, playerPokemonName, constants.ColorBrightPurple, constants.ColorReset)
-			}
-		}
+	return nil
+}
+
+// battleWild fights one round between a caught Pokemon and the wild Pokemon of the
+// current encounter. Both sides keep their remaining HP for the next round.
+func battleWild(cfg *Config, ref string) error {
+	encounter := cfg.Encounter
+	if encounter == nil {
+		return fmt.Errorf("%sthere's no wild Pokemon to battle. Use 'explore <area>' to find one, or 'battle <your_pokemon> <opponent_pokemon>' for a practice battle%s", constants.ColorYellow, constants.ColorReset)
+	}
+	playerPokemon, err := resolvePartyPokemon(cfg, ref, "battle with")
+	if err != nil {
+		return err
+	}
+	playerPokemonName := playerPokemon.DisplayName()
+
+	fighter := battle.NewCombatant(playerPokemon.PokemonData, constants.ColorGreen)
+	if hp, fought := encounter.FighterHP[playerPokemon.UID]; fought {
+		fighter.HP = hp
+		fighter.Status = encounter.FighterStatus[playerPokemon.UID]
+	}
+	if fighter.Fainted() {
+		return fmt.Errorf("%s%s%s has fainted and can't fight the wild %s again. Send out another Pokemon, throw a ball, or 'run'%s", constants.ColorBrightRed, playerPokemonName, constants.ColorYellow, encounter.Pokemon.Name, constants.ColorReset)
 	}
 
+	encounter.Turn++
+	fmt.Printf("\n%s--- Turn %s%d%s: %s%s%s vs wild %s%s%s %s(Lvl %d)%s ---%s\n",
+		constants.ColorCyan, constants.ColorYellow, encounter.Turn, constants.ColorCyan,
+		constants.ColorGreen, playerPokemonName, constants.ColorCyan,
+		constants.ColorRed, encounter.Pokemon.Name, constants.ColorCyan,
+		constants.ColorYellow, encounter.Level, constants.ColorCyan, constants.ColorReset)
+	battle.Round(cfg.Randomizer, &fighter, &encounter.Wild)
+	encounter.FighterHP[playerPokemon.UID] = max(fighter.HP, 0)
+	encounter.FighterStatus[playerPokemon.UID] = fighter.Status
+
+	fmt.Printf("  %s%s%s HP: %s%d%s/%d%s\n", constants.ColorGreen, playerPokemonName, constants.ColorReset, constants.ColorBrightGreen, max(fighter.HP, 0), constants.ColorReset, fighter.MaxHP, statusTag(fighter.Status))
+	printWildHP(encounter)
+
+	switch {
+	case encounter.Wild.Fainted():
+		fmt.Printf("%sThe wild %s%s%s fainted!%s\n", constants.ColorBrightGreen, constants.ColorYellow, encounter.Pokemon.Name, constants.ColorBrightGreen, constants.ColorReset)
+		cfg.Encounter = nil
+		changeHappiness(cfg, playerPokemon.UID, pokeapi.HappinessVictory)
+		awardXP(cfg, playerPokemon.UID, battle.XPReward(encounter.Pokemon))
+	case fighter.Fainted():
+		changeHappiness(cfg, playerPokemon.UID, pokeapi.HappinessFaint)
+		fmt.Printf("%sSend out another Pokemon with 'battle <your_pokemon>', throw a ball, or 'run'.%s\n", constants.ColorGray, constants.ColorReset)
+	default:
+		fmt.Printf("%sKeep battling, or try 'catch' now that it's weaker.%s\n", constants.ColorGray, constants.ColorReset)
+	}
 	return nil
 }
+
+// awardXP gives xpGained experience to the owned Pokemon with the given UID, stores it
+// back in the collection and checks for an evolution if it leveled up. Every level
+// gained makes it happier.
+func awardXP(cfg *Config, uid int, xpGained int) {
+	updatedPlayerPokemon := cfg.Collection[uid] // Get a fresh copy
+	previousLevel := updatedPlayerPokemon.Level
+	leveledUp := updatedPlayerPokemon.AddXP(xpGained) // AddXP modifies updatedPlayerPokemon directly
+	for range updatedPlayerPokemon.Level - previousLevel {
+		updatedPlayerPokemon.ChangeHappiness(pokeapi.HappinessLevelUp)
+	}
+	storeOwnedPokemon(cfg, updatedPlayerPokemon)
+
+	if leveledUp {
+		// The AddXP method already prints level up messages.
+		fmt.Printf("%s%s%s's stats may have changed due to leveling up!%s\n", constants.ColorGreen, constants.ColorYellow, updatedPlayerPokemon.DisplayName(), constants.ColorReset)
+
+		// Check for evolution after leveling up
+		evolved, err := CheckAndHandleEvolution(cfg, uid)
+		if err != nil {
+			// CheckAndHandleEvolution and performEvolution already color their errors, this is a fallback/wrapper
+			fmt.Printf("%sError during evolution check for %s%s%s: %v%s\n", constants.ColorRed, constants.ColorYellow, updatedPlayerPokemon.DisplayName(), constants.ColorRed, err, constants.ColorReset)
+		}
+		if evolved {
+			// The evolution messages are handled by CheckAndHandleEvolution.
+			fmt.Printf("%s--- %s%s%s has evolved! ---%s\n", constants.ColorBrightPurple, constants.ColorYellow, updatedPlayerPokemon.DisplayName(), constants.ColorBrightPurple, constants.ColorReset)
+		}
+	}
+}
+
+// leadBattler picks who fights the wild Pokemon when the player names no one: the
+// party's lead, or the next party member if the lead has fainted in this encounter.
+func leadBattler(cfg *Config) (pokeapi.UserPokemon, error) {
+	for _, p := range partyPokemon(cfg) {
+		if hp, fought := cfg.Encounter.FighterHP[p.UID]; !fought || hp > 0 {
+			return p, nil
+		}
+	}
+	if len(cfg.Party) == 0 {
+		return pokeapi.UserPokemon{}, fmt.Errorf("%syou have no Pokemon in your party to battle with. Withdraw one from the PC first%s", constants.ColorYellow, constants.ColorReset)
+	}
+	return pokeapi.UserPokemon{}, fmt.Errorf("%severy Pokemon in your party has fainted. Throw a ball or 'run'%s", constants.ColorYellow, constants.ColorReset)
+}
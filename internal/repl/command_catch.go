@@ -2,30 +2,49 @@ package repl
 
 import (
 	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/voidarchive/pokedex/internal/battle"
 	"github.com/voidarchive/pokedex/internal/pokeapi"
 	"github.com/voidarchive/pokedex/internal/shared/constants"
 )
 
-// ansiReset is already defined in command_catch.go, using ColorReset from colors.go is better for consistency.
-// We'll assume ColorReset is available globally in the package or use repl.ColorReset if not.
+// escapeMessages are what the player sees when a Pokemon breaks free, indexed by the
+// number of shake checks the ball passed first.
+var escapeMessages = [...]string{
+	"Oh no!",
+	"Aww! It appeared to be caught!",
+	"Aargh! Almost had it!",
+	"Gah! It was so close, too!",
+}
 
+// commandCatch throws a ball at the wild Pokemon of the current encounter. The wild
+// Pokemon's name is optional, so both 'catch greatball' and 'catch pikachu greatball' work.
 func commandCatch(cfg *Config, args ...string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("%susage: catch <pokemon_name> [pokeball_type (e.g., pokeball, greatball)]%s", constants.ColorYellow, constants.ColorReset)
+	if cfg.Encounter == nil {
+		return errNoEncounter("catch")
 	}
-	pokemonName := args[0]
+	pokemonData := cfg.Encounter.Pokemon
 
 	ballToUseKey := DefaultBall // from types.go
-	if len(args) > 1 {
-		ballToUseKey = strings.ToLower(args[1])
+	switch {
+	case len(args) > 0 && args[0] == pokemonData.Name:
+		if len(args) > 1 {
+			ballToUseKey = strings.ToLower(args[1])
+		}
+	case len(args) == 1 && isPokeballKey(args[0]):
+		ballToUseKey = strings.ToLower(args[0])
+	case len(args) > 0:
+		return fmt.Errorf("%sthere's no wild %s%s%s here, only the wild %s%s%s you're facing%s", constants.ColorYellow, constants.ColorBrightRed, args[0], constants.ColorYellow, constants.ColorBrightYellow, pokemonData.Name, constants.ColorYellow, constants.ColorReset)
 	}
 
 	chosenBall, ballExists := KnownPokeballs[ballToUseKey]
 	if !ballExists {
-		return fmt.Errorf("%sunknown pokeball type: %s%s%s. Available: pokeball, greatball, ultraball%s", constants.ColorYellow, constants.ColorBrightRed, ballToUseKey, constants.ColorYellow, constants.ColorReset)
+		return fmt.Errorf("%sunknown pokeball type: %s%s%s. Available: %s%s", constants.ColorYellow, constants.ColorBrightRed, ballToUseKey, constants.ColorYellow, strings.Join(slices.Sorted(maps.Keys(KnownPokeballs)), ", "), constants.ColorReset)
 	}
 
 	// Check inventory
@@ -33,70 +52,96 @@ func commandCatch(cfg *Config, args ...string) error {
 		return fmt.Errorf("%syou don't have any %s%s%s left%s", constants.ColorYellow, chosenBall.Color, chosenBall.Name, constants.ColorYellow, constants.ColorReset)
 	}
 
-	// Decrement ball count (attempt is made)
-	cfg.Inventory[ballToUseKey]--
-
-	fmt.Printf("Throwing a %s%s%s at %s%s%s...\n", chosenBall.Color, chosenBall.Name, constants.ColorReset, constants.ColorYellow, pokemonName, constants.ColorReset)
-
-	// Check if already caught before making an API call
-	if _, caught := cfg.Pokedex[pokemonName]; caught {
-		fmt.Printf("%s%s%s has already been caught!%s\n", constants.ColorYellow, constants.ColorBrightYellow, pokemonName, constants.ColorReset)
-		fmt.Printf("%sYou can inspect it using the 'inspect' command.%s\n", constants.ColorGray, constants.ColorReset)
-		// Note: The ball was used, so inventory remains decremented.
-		return nil
+	if !roomForPokemon(cfg) {
+		return fmt.Errorf("%syour party and PC boxes are full: there's no room for another Pokemon%s", constants.ColorYellow, constants.ColorReset)
 	}
 
-	pokemonData, err := cfg.PokeapiClient.FetchPokemon(pokemonName)
+	speciesName := pokemonData.SpeciesName()
+	species, err := cfg.PokeapiClient.FetchPokemonSpecies(speciesName)
 	if err != nil {
-		return err // API client errors are already formatted or will be by the top-level handler
+		return explainAPIError(err, fmt.Sprintf("there's no species data for '%s%s%s', so it can't be caught", constants.ColorBrightRed, speciesName, constants.ColorYellow))
 	}
 
-	const maxRollValue = 400
-	successThreshold := maxRollValue - pokemonData.BaseExperience
-
-	// Apply Pokeball modifier
-	successThreshold = int(float64(successThreshold) * chosenBall.CatchRateMod)
+	// Decrement ball count (attempt is made)
+	cfg.Inventory[ballToUseKey]--
 
-	const minSuccessPoints = 20  // Adjusted slightly if needed, or keep as is
-	const maxSuccessPoints = 390 // Adjusted slightly, ensuring it's less than maxRollValue
+	fmt.Printf("Throwing a %s%s%s at the wild %s%s%s...\n", chosenBall.Color, chosenBall.Name, constants.ColorReset, constants.ColorYellow, pokemonData.Name, constants.ColorReset)
 
-	if pokemonData.BaseExperience <= 0 {
-		successThreshold = maxSuccessPoints
-	} else {
-		if successThreshold < minSuccessPoints {
-			successThreshold = minSuccessPoints
-		}
-		if successThreshold > maxSuccessPoints {
-			successThreshold = maxSuccessPoints
-		}
+	encounter := cfg.Encounter
+	encounter.Turn++
+	ballContext := battle.BallContext{
+		Turn:        encounter.Turn,
+		TargetTypes: typeNames(pokemonData),
+		Night:       timeOfDay(cfg.now()) == "night",
+	}
+	// A wild Pokemon weakened or given a status in battle is easier to catch.
+	result := battle.AttemptCapture(cfg.Randomizer, battle.CaptureAttempt{
+		CaptureRate: species.CaptureRate,
+		MaxHP:       encounter.Wild.MaxHP,
+		CurrentHP:   encounter.Wild.HP,
+		Status:      encounter.Wild.Status,
+		BallBonus:   chosenBall.CatchBonus(ballContext),
+	})
+
+	// The ball wobbles once per passed shake check; the fourth check is the click.
+	for range min(result.Shakes, 3) {
+		fmt.Printf("  %s...wobble...%s\n", constants.ColorGray, constants.ColorReset)
 	}
 
-	roll := cfg.Randomizer.Intn(maxRollValue)
-
-	if roll < successThreshold {
+	if result.Caught {
+		cfg.Encounter = nil
+		fmt.Printf("  %sClick!%s\n", constants.ColorBrightWhite, constants.ColorReset)
 		fmt.Printf("%s%s%s%s was caught!%s\n", chosenBall.Color, constants.ColorBrightGreen, pokemonData.Name, chosenBall.Color, constants.ColorReset)
+		cfg.Dex.markCaught(speciesName)
 		fmt.Printf("%sYou may now inspect it with the inspect command.%s\n", constants.ColorGray, constants.ColorReset)
 
-		newUserPokemon := pokeapi.UserPokemon{
-			PokemonData:     pokemonData,
-			Level:           1,
-			CurrentXP:       0,
-			CaughtTimestamp: time.Now().UnixNano(), // Set caught timestamp
+		newUserPokemon := pokeapi.NewUserPokemon(pokemonData, encounter.Level)
+		newUserPokemon.CaughtTimestamp = time.Now().UnixNano()
+		newUserPokemon.Gender = rollGender(cfg.Randomizer, species.GenderRate)
+		newUserPokemon.Happiness = pokeapi.DefaultHappiness
+		if species.BaseHappiness != nil {
+			newUserPokemon.Happiness = *species.BaseHappiness
 		}
-		newUserPokemon.XPToNextLevel = newUserPokemon.CalculateNewXPToNextLevel() // Calculate based on its level
-
-		cfg.Pokedex[newUserPokemon.Name] = newUserPokemon
 
-		if len(cfg.Party) < MaxPartySize {
-			cfg.Party = append(cfg.Party, newUserPokemon)
-			fmt.Printf("%s%s%s has been added to your party!%s\n", chosenBall.Color, newUserPokemon.Name, constants.ColorReset, constants.ColorReset)
+		newUserPokemon, inParty := addOwnedPokemon(cfg, newUserPokemon)
+		if inParty {
+			fmt.Printf("%s%s%s has been added to your party as %s#%d%s!%s\n", chosenBall.Color, newUserPokemon.Name, constants.ColorReset, constants.ColorYellow, newUserPokemon.UID, constants.ColorReset, constants.ColorReset)
 		} else {
-			fmt.Printf("%s%s%s has been sent to your Pokedex storage as your party is full.%s\n", chosenBall.Color, newUserPokemon.Name, constants.ColorReset, constants.ColorReset)
+			fmt.Printf("%s%s%s (%s#%d%s) was sent to %s%s%s as your party is full.%s\n", chosenBall.Color, newUserPokemon.Name, constants.ColorReset, constants.ColorYellow, newUserPokemon.UID, constants.ColorReset, constants.ColorYellow, whereIs(cfg, newUserPokemon.UID), constants.ColorReset, constants.ColorReset)
 		}
+		fmt.Printf("%sGive it a nickname with 'nickname #%d <name>'.%s\n", constants.ColorGray, newUserPokemon.UID, constants.ColorReset)
 
 	} else {
-		fmt.Printf("%sOh no! %s%s%s escaped!%s\n", constants.ColorRed, chosenBall.Color, pokemonData.Name, constants.ColorRed, constants.ColorReset)
+		fmt.Printf("%s%s The wild %s%s%s broke free!%s\n", constants.ColorRed, escapeMessages[result.Shakes], chosenBall.Color, pokemonData.Name, constants.ColorRed, constants.ColorReset)
 	}
 
 	return nil
 }
+
+// rollGender picks the gender of a newly caught Pokemon from its species' gender rate,
+// the chance of being female in eighths (-1 for genderless species).
+func rollGender(r *rand.Rand, genderRate int) string {
+	switch {
+	case genderRate < 0:
+		return ""
+	case r.Intn(8) < genderRate:
+		return "female"
+	default:
+		return "male"
+	}
+}
+
+// typeNames lists the type names of a Pokemon, e.g. ["bug", "flying"].
+func typeNames(pokemon pokeapi.PokemonData) []string {
+	names := make([]string, len(pokemon.Types))
+	for i, t := range pokemon.Types {
+		names[i] = t.Type.Name
+	}
+	return names
+}
+
+// isPokeballKey reports whether arg names a known ball, e.g. "greatball".
+func isPokeballKey(arg string) bool {
+	_, ok := KnownPokeballs[strings.ToLower(arg)]
+	return ok
+}
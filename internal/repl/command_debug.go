@@ -0,0 +1,38 @@
+package repl
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/voidarchive/pokedex/internal/shared/constants"
+	"github.com/voidarchive/pokedex/internal/shared/logging"
+)
+
+// commandDebug turns the API request and cache trace on or off at runtime.
+// Without arguments it reports whether the trace is currently shown.
+func commandDebug(cfg *Config, args ...string) error {
+	if cfg.LogLevel == nil {
+		return fmt.Errorf("%slogging is not available in this session%s", constants.ColorYellow, constants.ColorReset)
+	}
+
+	if len(args) == 0 {
+		state := "off"
+		if cfg.LogLevel.Level() <= slog.LevelDebug {
+			state = "on"
+		}
+		fmt.Printf("%sDebug logging is %s%s%s. Use 'debug on' or 'debug off' to change it.%s\n", constants.ColorGray, constants.ColorYellow, state, constants.ColorGray, constants.ColorReset)
+		return nil
+	}
+
+	switch args[0] {
+	case "on":
+		cfg.LogLevel.Set(slog.LevelDebug)
+		fmt.Printf("%sDebug logging enabled. API requests and cache activity will be logged.%s\n", constants.ColorGreen, constants.ColorReset)
+	case "off":
+		cfg.LogLevel.Set(logging.LevelOff)
+		fmt.Printf("%sDebug logging disabled.%s\n", constants.ColorGreen, constants.ColorReset)
+	default:
+		return fmt.Errorf("%susage: debug <on|off>%s", constants.ColorYellow, constants.ColorReset)
+	}
+	return nil
+}
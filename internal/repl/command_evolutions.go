@@ -0,0 +1,82 @@
+package repl
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/shared/constants"
+)
+
+// commandEvolutions draws the evolution chain of a Pokemon as a tree, with what it
+// takes to evolve on each branch. Species the player has caught are highlighted.
+func commandEvolutions(cfg *Config, args ...string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%susage: evolutions <pokemon_name>%s", constants.ColorYellow, constants.ColorReset)
+	}
+	// The player can name one of their own Pokemon by ID or nickname, or any species.
+	speciesName := args[0]
+	if matches := matchPokemon(cfg, speciesName); len(matches) > 0 {
+		speciesName = matches[0].SpeciesName()
+	}
+
+	species, err := cfg.PokeapiClient.FetchPokemonSpecies(speciesName)
+	if err != nil {
+		return explainAPIError(err, fmt.Sprintf("there's no Pokemon species called '%s%s%s'. Check the spelling: names are lowercase with dashes, e.g. 'mr-mime'", constants.ColorBrightRed, speciesName, constants.ColorYellow))
+	}
+	if species.EvolutionChain.URL == "" {
+		fmt.Printf("%s%s%s doesn't evolve.%s\n", constants.ColorYellow, species.Name, constants.ColorGray, constants.ColorReset)
+		return nil
+	}
+	chain, err := cfg.PokeapiClient.FetchEvolutionChain(species.EvolutionChain.URL)
+	if err != nil {
+		return explainAPIError(err, fmt.Sprintf("there's no evolution chain for '%s%s%s'", constants.ColorBrightRed, species.Name, constants.ColorYellow))
+	}
+
+	// Like the Pokedex, count species caught before, even if released or evolved since.
+	caught := cfg.Dex.Caught
+	fmt.Printf("\n%sEvolution chain of %s%s%s:%s\n", constants.ColorCyan, constants.ColorYellow, species.Name, constants.ColorCyan, constants.ColorReset)
+	fmt.Println(speciesLabel(chain.Chain.Species.Name, caught))
+	printEvolutionBranches(chain.Chain, "", caught)
+	if len(caught) > 0 {
+		fmt.Printf("%s(* caught)%s\n", constants.ColorGray, constants.ColorReset)
+	}
+	return nil
+}
+
+// printEvolutionBranches prints the evolutions of link below it, each prefixed with
+// the tree lines of its ancestors.
+func printEvolutionBranches(link pokeapi.CorrectedChainLink, prefix string, caught map[string]bool) {
+	for i, next := range link.EvolvesTo {
+		branch, indent := "|-- ", "|   "
+		if i == len(link.EvolvesTo)-1 {
+			branch, indent = "`-- ", "    "
+		}
+		fmt.Printf("%s%s%s%s[%s]%s -> %s\n", constants.ColorGray, prefix, branch, constants.ColorGreen, evolutionConditions(next.EvolutionDetails), constants.ColorReset, speciesLabel(next.Species.Name, caught))
+		printEvolutionBranches(next, prefix+indent, caught)
+	}
+}
+
+// evolutionConditions describes the ways to evolve into a species, e.g. "Lv 16" or
+// "level up, at eterna-forest or use leaf-stone".
+func evolutionConditions(details []pokeapi.EvolutionDetail) string {
+	var ways []string
+	for _, detail := range details {
+		if way := detail.String(); !slices.Contains(ways, way) {
+			ways = append(ways, way)
+		}
+	}
+	if len(ways) == 0 {
+		return "unknown"
+	}
+	return strings.Join(ways, " or ")
+}
+
+// speciesLabel shows a species in the tree, highlighted and starred if it was caught.
+func speciesLabel(name string, caught map[string]bool) string {
+	if caught[name] {
+		return fmt.Sprintf("%s%s *%s", constants.ColorBrightGreen, name, constants.ColorReset)
+	}
+	return fmt.Sprintf("%s%s%s", constants.ColorWhite, name, constants.ColorReset)
+}
@@ -3,13 +3,21 @@ package repl
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/voidarchive/pokedex/internal/shared/constants"
 )
 
 func commandExplore(cfg *Config, args ...string) error {
+	args, method, err := parseMethodFlag(args)
+	if err != nil {
+		return err
+	}
 	if len(args) == 0 {
-		return fmt.Errorf("%syou must provide a location area name or number (if choices are available from 'map')%s", constants.ColorYellow, constants.ColorReset)
+		return fmt.Errorf("%syou must provide a location area name or number (if choices are available from 'areas' or 'map')%s", constants.ColorYellow, constants.ColorReset)
+	}
+	if cfg.CurrentLocation == "" {
+		return errNoLocation()
 	}
 
 	areaIdentifier := args[0]
@@ -42,28 +50,49 @@ func commandExplore(cfg *Config, args ...string) error {
 
 	locationDetail, err := cfg.PokeapiClient.FetchLocationAreaDetail(areaNameToExplore)
 	if err != nil {
-		return err // Error from FetchLocationAreaDetail should be colored by the client or caller
+		return explainAPIError(err, fmt.Sprintf("there's no location area called '%s%s%s'. Run 'areas' to list the areas here, then 'explore <number>' to pick one", constants.ColorBrightRed, areaNameToExplore, constants.ColorYellow))
+	}
+	if locationDetail.Location.Name != cfg.CurrentLocation {
+		return fmt.Errorf("%s%s%s is in %s%s%s, but you're at %s%s%s. Run 'areas' to see what's here, or 'travel %s' to go there%s", constants.ColorBrightRed, areaNameToExplore, constants.ColorYellow, constants.ColorBrightYellow, locationDetail.Location.Name, constants.ColorYellow, constants.ColorBrightYellow, cfg.CurrentLocation, constants.ColorYellow, locationDetail.Location.Name, constants.ColorReset)
 	}
 
 	fmt.Printf("%sFound Pokemon:%s\n", constants.ColorGreen, constants.ColorReset)
 	if len(locationDetail.PokemonEncounters) == 0 {
 		fmt.Printf("  %sNo Pokemon found in this area.%s\n", constants.ColorGray, constants.ColorReset)
-	} else {
-		for _, encounter := range locationDetail.PokemonEncounters {
-			fmt.Printf("  %s- %s%s%s\n", constants.ColorGray, constants.ColorWhite, encounter.Pokemon.Name, constants.ColorReset)
+		return nil
+	}
+	for _, encounter := range locationDetail.PokemonEncounters {
+		fmt.Printf("  %s- %s%s%s", constants.ColorGray, constants.ColorWhite, encounter.Pokemon.Name, constants.ColorReset)
+		if minLevel, maxLevel, ok := encounter.LevelRange(); ok {
+			fmt.Printf(" %s(%s, Lvl %d-%d)%s", constants.ColorGray, strings.Join(encounter.Methods(), ", "), minLevel, maxLevel, constants.ColorReset)
 		}
+		fmt.Println()
 	}
 
-	// Random Encounter Logic
-	if len(locationDetail.PokemonEncounters) > 0 {
-		// Select one Pokemon randomly from the list of possible encounters in this area
-		randomIndex := cfg.Randomizer.Intn(len(locationDetail.PokemonEncounters))
-		encounteredPokemon := locationDetail.PokemonEncounters[randomIndex].Pokemon
+	table := locationDetail.EncounterTable(method, encounterAvailable(cfg))
+	if len(table) == 0 {
+		fmt.Printf("\n%sNo Pokemon can be found here by %s%s%s right now.%s\n", constants.ColorYellow, constants.ColorBrightYellow, method, constants.ColorYellow, constants.ColorReset)
+		if others := otherMethods(locationDetail, method); len(others) > 0 {
+			fmt.Printf("%sTry 'explore %s --method <method>' with one of: %s.%s\n", constants.ColorGray, locationDetail.Name, strings.Join(others, ", "), constants.ColorReset)
+		}
+		return nil
+	}
+	slot := pickEncounterSlot(cfg.Randomizer, table)
+	areaEncounter := slot.Pokemon
+
+	wildPokemon, err := cfg.PokeapiClient.FetchPokemon(areaEncounter)
+	if err != nil {
+		return explainAPIError(err, fmt.Sprintf("the wild '%s%s%s' in this area has no Pokemon data", constants.ColorBrightRed, areaEncounter, constants.ColorYellow))
+	}
 
-		fmt.Printf("\n%sA wild %s%s%s has appeared!%s\n", constants.ColorBrightYellow, constants.ColorYellow, encounteredPokemon.Name, constants.ColorBrightYellow, constants.ColorReset)
-		// Future: Add options like battle, catch, run here.
-		// For now, just the appearance is the "encounter".
+	if cfg.Encounter != nil {
+		fmt.Printf("%sYou left the wild %s behind.%s\n", constants.ColorGray, cfg.Encounter.Pokemon.Name, constants.ColorReset)
 	}
+	cfg.Encounter = newEncounter(wildPokemon, slotLevel(cfg.Randomizer, slot), locationDetail.Name)
+	cfg.Dex.markSeen(wildPokemon.SpeciesName())
+
+	fmt.Printf("\n%sA wild %s%s%s %s(Lvl %d)%s has appeared!%s\n", constants.ColorBrightYellow, constants.ColorYellow, wildPokemon.Name, constants.ColorBrightYellow, constants.ColorCyan, cfg.Encounter.Level, constants.ColorBrightYellow, constants.ColorReset)
+	fmt.Printf("%sWhat will you do? 'battle <your_pokemon>' to weaken it, 'catch [pokeball_type]' to throw a ball, or 'run'.%s\n", constants.ColorGray, constants.ColorReset)
 
 	// Consider clearing CurrentAreaChoices if we want to force 'map' before every 'explore <number>'
 	// cfg.CurrentAreaChoices = nil
@@ -2,7 +2,11 @@ package repl
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/voidarchive/pokedex/internal/pokeapi"
 	"github.com/voidarchive/pokedex/internal/shared/constants"
 )
 
@@ -10,19 +14,32 @@ func commandInspect(cfg *Config, args ...string) error {
 	if len(args) == 0 {
 		return fmt.Errorf("%syou must provide a Pokemon name to inspect%s", constants.ColorYellow, constants.ColorReset)
 	}
-	pokemonName := args[0]
-
-	pokemon, caught := cfg.Pokedex[pokemonName]
-	if !caught {
-		return fmt.Errorf("%syou have not caught %s%s%s%s", constants.ColorYellow, constants.ColorBrightRed, pokemonName, constants.ColorYellow, constants.ColorReset)
+	pokemon, err := resolvePokemon(cfg, args[0])
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("\n%s--- Inspecting: %s%s%s ---%s\n", constants.ColorCyan, constants.ColorBrightYellow, pokemon.Name, constants.ColorCyan, constants.ColorReset)
+	fmt.Printf("\n%s--- Inspecting: %s%s%s ---%s\n", constants.ColorCyan, constants.ColorBrightYellow, pokemonLabel(pokemon), constants.ColorCyan, constants.ColorReset)
+	printPokedexEntry(cfg, pokemon)
 	fmt.Printf("  %sName:%s %s%s%s\n", constants.ColorGreen, constants.ColorReset, constants.ColorWhite, pokemon.Name, constants.ColorReset)
+	if pokemon.Nickname != "" {
+		fmt.Printf("  %sNickname:%s %s%s%s\n", constants.ColorGreen, constants.ColorReset, constants.ColorWhite, pokemon.Nickname, constants.ColorReset)
+	}
+	fmt.Printf("  %sID:%s %s%d%s\n", constants.ColorGreen, constants.ColorReset, constants.ColorWhite, pokemon.UID, constants.ColorReset)
+	if pokemon.CaughtTimestamp != 0 {
+		fmt.Printf("  %sCaught:%s %s%s%s\n", constants.ColorGreen, constants.ColorReset, constants.ColorWhite, time.Unix(0, pokemon.CaughtTimestamp).Format("2006-01-02 15:04"), constants.ColorReset)
+	}
 	fmt.Printf("  %sHeight:%s %s%d%s\n", constants.ColorGreen, constants.ColorReset, constants.ColorWhite, pokemon.Height, constants.ColorReset)
 	fmt.Printf("  %sWeight:%s %s%d%s\n", constants.ColorGreen, constants.ColorReset, constants.ColorWhite, pokemon.Weight, constants.ColorReset)
+	if pokemon.Gender != "" {
+		fmt.Printf("  %sGender:%s %s%s%s\n", constants.ColorGreen, constants.ColorReset, constants.ColorWhite, pokemon.Gender, constants.ColorReset)
+	}
+	if pokemon.HeldItem != "" {
+		fmt.Printf("  %sHeld item:%s %s%s%s\n", constants.ColorGreen, constants.ColorReset, constants.ColorWhite, pokemon.HeldItem, constants.ColorReset)
+	}
 
 	fmt.Printf("  %sLevel:%s %s%d%s\n", constants.ColorGreen, constants.ColorReset, constants.ColorBrightCyan, pokemon.Level, constants.ColorReset)
+	fmt.Printf("  %sHappiness:%s %s%d%s/%d %s%s%s\n", constants.ColorGreen, constants.ColorReset, constants.ColorBrightCyan, pokemon.Happiness, constants.ColorReset, pokeapi.MaxHappiness, constants.ColorGray, happinessText(pokemon.Happiness), constants.ColorReset)
 	fmt.Printf("  %sXP:%s %s%d%s/%s%d%s\n", constants.ColorGreen, constants.ColorReset, constants.ColorBrightCyan, pokemon.CurrentXP, constants.ColorReset, constants.ColorCyan, pokemon.XPToNextLevel, constants.ColorReset)
 
 	fmt.Printf("  %sStats:%s\n", constants.ColorGreen, constants.ColorReset)
@@ -34,7 +51,95 @@ func commandInspect(cfg *Config, args ...string) error {
 		// We can add specific colors per type later if desired
 		fmt.Printf("    %s- %s%s%s\n", constants.ColorPurple, constants.ColorWhite, typeInfo.Type.Name, constants.ColorReset)
 	}
+	printEvolutionHistory(pokemon)
 	fmt.Println(constants.ColorCyan + "------------------------" + constants.ColorReset)
 
 	return nil
 }
+
+// printEvolutionHistory lists the evolutions of an owned Pokemon, oldest first.
+func printEvolutionHistory(pokemon pokeapi.UserPokemon) {
+	if len(pokemon.Evolutions) == 0 {
+		return
+	}
+	fmt.Printf("  %sEvolution history:%s\n", constants.ColorGreen, constants.ColorReset)
+	for _, evolution := range pokemon.Evolutions {
+		how := evolution.Trigger
+		if evolution.Item != "" {
+			how = evolution.Item
+		}
+		fmt.Printf("    %s- %s%s -> %s%s at Lv %d by %s%s, %s%s\n",
+			constants.ColorPurple, constants.ColorWhite, evolution.From, evolution.To, constants.ColorReset,
+			evolution.Level, how, constants.ColorGray, time.Unix(0, evolution.Timestamp).Format("2006-01-02 15:04"), constants.ColorReset)
+	}
+}
+
+// printPokedexEntry shows the species' Pokedex entry: number, genus, flavor text and
+// species facts. Species data is optional here; if it can't be fetched, inspect still
+// shows everything stored on the caught Pokemon.
+func printPokedexEntry(cfg *Config, pokemon pokeapi.UserPokemon) {
+	speciesName := pokemon.SpeciesName()
+
+	species, err := cfg.PokeapiClient.FetchPokemonSpecies(speciesName)
+	if err != nil {
+		fmt.Printf("  %sPokedex entry unavailable: %v%s\n", constants.ColorGray, explainAPIError(err, "no species data found"), constants.ColorReset)
+		return
+	}
+
+	fmt.Printf("  %s#%03d%s %s%s%s", constants.ColorYellow, species.ID, constants.ColorReset, constants.ColorBrightWhite, species.Name, constants.ColorReset)
+	if genus, ok := species.Genus(pokeapi.DefaultLanguage); ok {
+		fmt.Printf(" %s- the %s%s", constants.ColorWhite, genus, constants.ColorReset)
+	}
+	if species.IsLegendary {
+		fmt.Printf(" %s[Legendary]%s", constants.ColorBrightYellow, constants.ColorReset)
+	}
+	if species.IsMythical {
+		fmt.Printf(" %s[Mythical]%s", constants.ColorBrightPurple, constants.ColorReset)
+	}
+	fmt.Println()
+
+	if text, version, ok := species.FlavorText(pokeapi.DefaultLanguage); ok {
+		for _, line := range wrapText(text, 60) {
+			fmt.Printf("    %s%s%s\n", constants.ColorGray, line, constants.ColorReset)
+		}
+		fmt.Printf("    %s(Pokemon %s)%s\n", constants.ColorGray, version, constants.ColorReset)
+	}
+
+	habitat := "unknown"
+	if species.Habitat != nil {
+		habitat = species.Habitat.Name
+	}
+	baseHappiness := "unknown"
+	if species.BaseHappiness != nil {
+		baseHappiness = strconv.Itoa(*species.BaseHappiness)
+	}
+	generation := strings.ToUpper(strings.TrimPrefix(species.Generation.Name, "generation-"))
+
+	fmt.Printf("  %sGeneration:%s %s%s%s  %sHabitat:%s %s%s%s  %sGrowth rate:%s %s%s%s\n",
+		constants.ColorGreen, constants.ColorReset, constants.ColorWhite, generation, constants.ColorReset,
+		constants.ColorGreen, constants.ColorReset, constants.ColorWhite, habitat, constants.ColorReset,
+		constants.ColorGreen, constants.ColorReset, constants.ColorWhite, species.GrowthRate.Name, constants.ColorReset)
+	fmt.Printf("  %sCapture rate:%s %s%d%s  %sBase happiness:%s %s%s%s\n",
+		constants.ColorGreen, constants.ColorReset, constants.ColorWhite, species.CaptureRate, constants.ColorReset,
+		constants.ColorGreen, constants.ColorReset, constants.ColorWhite, baseHappiness, constants.ColorReset)
+}
+
+// wrapText splits text into lines of at most width characters, breaking on spaces.
+func wrapText(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len([]rune(line))+1+len([]rune(word)) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
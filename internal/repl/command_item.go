@@ -0,0 +1,93 @@
+package repl
+
+import (
+	"fmt"
+
+	"github.com/voidarchive/pokedex/internal/shared/constants"
+)
+
+// commandUse uses an item from the inventory on a Pokemon, e.g. 'use fire-stone eevee'.
+// Berries make it happier, and evolution stones drive the "use-item" evolutions; the
+// item is only used up if it has an effect. Like in the games, a stone evolution can't
+// be cancelled, and an Everstone doesn't stop it.
+func commandUse(cfg *Config, args ...string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("%susage: use <item> <pokemon_name>%s", constants.ColorYellow, constants.ColorReset)
+	}
+	item := args[0]
+	if cfg.Inventory[item] <= 0 {
+		return errNoItem(item)
+	}
+	pokemon, err := resolvePokemon(cfg, args[1])
+	if err != nil {
+		return err
+	}
+	if _, ok := happinessItems[item]; ok {
+		return useHappinessItem(cfg, item, pokemon)
+	}
+
+	evolution, detail, found, err := findEvolution(cfg, pokemon, "use-item", item, "")
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%sthe %s%s%s won't have any effect on %s%s", constants.ColorYellow, constants.ColorBrightRed, item, constants.ColorYellow, pokemon.DisplayName(), constants.ColorReset)
+	}
+
+	cfg.Inventory[item]--
+	fmt.Printf("%sYou used the %s%s%s on %s%s%s.%s\n", constants.ColorCyan, constants.ColorYellow, item, constants.ColorCyan, constants.ColorYellow, pokemon.DisplayName(), constants.ColorCyan, constants.ColorReset)
+	fmt.Printf("%sWhat? %s%s%s is evolving!%s\n", constants.ColorBrightPurple, constants.ColorYellow, pokemon.DisplayName(), constants.ColorBrightPurple, constants.ColorReset)
+	_, err = performEvolution(cfg, pokemon, evolution, detail)
+	return err
+}
+
+// commandGive lets a Pokemon hold an item from the inventory, e.g. an Everstone to keep it
+// from evolving. An item it was already holding goes back to the inventory.
+func commandGive(cfg *Config, args ...string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("%susage: give <item> <pokemon_name>%s", constants.ColorYellow, constants.ColorReset)
+	}
+	item := args[0]
+	if cfg.Inventory[item] <= 0 {
+		return errNoItem(item)
+	}
+	pokemon, err := resolvePokemon(cfg, args[1])
+	if err != nil {
+		return err
+	}
+
+	if pokemon.HeldItem != "" {
+		cfg.Inventory[pokemon.HeldItem]++
+		fmt.Printf("%sYou took the %s%s%s from %s and put it in your bag.%s\n", constants.ColorGray, constants.ColorYellow, pokemon.HeldItem, constants.ColorGray, pokemon.DisplayName(), constants.ColorReset)
+	}
+	cfg.Inventory[item]--
+	pokemon.HeldItem = item
+	storeOwnedPokemon(cfg, pokemon)
+	fmt.Printf("%s%s%s is now holding the %s%s%s.%s\n", constants.ColorYellow, pokemon.DisplayName(), constants.ColorCyan, constants.ColorYellow, item, constants.ColorCyan, constants.ColorReset)
+	return nil
+}
+
+// commandTake puts the item a Pokemon holds back into the inventory.
+func commandTake(cfg *Config, args ...string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%susage: take <pokemon_name>%s", constants.ColorYellow, constants.ColorReset)
+	}
+	pokemon, err := resolvePokemon(cfg, args[0])
+	if err != nil {
+		return err
+	}
+	if pokemon.HeldItem == "" {
+		return fmt.Errorf("%s%s isn't holding anything%s", constants.ColorYellow, pokemon.DisplayName(), constants.ColorReset)
+	}
+
+	item := pokemon.HeldItem
+	cfg.Inventory[item]++
+	pokemon.HeldItem = ""
+	storeOwnedPokemon(cfg, pokemon)
+	fmt.Printf("%sYou took the %s%s%s from %s%s%s.%s\n", constants.ColorCyan, constants.ColorYellow, item, constants.ColorCyan, constants.ColorYellow, pokemon.DisplayName(), constants.ColorCyan, constants.ColorReset)
+	return nil
+}
+
+func errNoItem(item string) error {
+	return fmt.Errorf("%syou don't have any %s%s%s. Type 'inventory' to see your items%s", constants.ColorYellow, constants.ColorBrightRed, item, constants.ColorYellow, constants.ColorReset)
+}
@@ -23,7 +23,7 @@ func commandMapf(cfg *Config, args ...string) error {
 	for i, area := range cfg.CurrentAreaChoices {
 		fmt.Printf("  %s%d%s: %s%s%s\n", constants.ColorYellow, i+1, constants.ColorReset, constants.ColorWhite, area.Name, constants.ColorReset)
 	}
-	fmt.Printf("\n%sType 'explore <number>' or 'explore <full_area_name>' to see Pokemon in an area.%s\n", constants.ColorGray, constants.ColorReset)
+	fmt.Printf("\n%sTo explore an area, 'travel' to its location, then type 'explore <number>' or 'explore <full_area_name>'.%s\n", constants.ColorGray, constants.ColorReset)
 
 	cfg.NextLocationAreaURL = locationData.Next
 	cfg.PrevLocationAreaURL = locationData.Previous
@@ -47,7 +47,7 @@ func commandMapb(cfg *Config, args ...string) error {
 	for i, area := range cfg.CurrentAreaChoices {
 		fmt.Printf("  %s%d%s: %s%s%s\n", constants.ColorYellow, i+1, constants.ColorReset, constants.ColorWhite, area.Name, constants.ColorReset)
 	}
-	fmt.Printf("\n%sType 'explore <number>' or 'explore <full_area_name>' to see Pokemon in an area.%s\n", constants.ColorGray, constants.ColorReset)
+	fmt.Printf("\n%sTo explore an area, 'travel' to its location, then type 'explore <number>' or 'explore <full_area_name>'.%s\n", constants.ColorGray, constants.ColorReset)
 
 	cfg.NextLocationAreaURL = locationData.Next
 	cfg.PrevLocationAreaURL = locationData.Previous
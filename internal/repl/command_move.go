@@ -0,0 +1,85 @@
+package repl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/voidarchive/pokedex/internal/shared/constants"
+	"github.com/voidarchive/pokedex/internal/world"
+)
+
+// commandMove walks to a location next to the player's, given by the direction of the
+// path leading there (e.g. "north" or "n") or by its name.
+func commandMove(cfg *Config, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%susage: move <direction|location>%s", constants.ColorYellow, constants.ColorReset)
+	}
+	regionMap, err := currentMap(cfg)
+	if err != nil {
+		return err
+	}
+
+	target := strings.ToLower(args[0])
+	if direction, ok := world.ParseDirection(target); ok {
+		next, ok := regionMap.Neighbor(cfg.CurrentLocation, direction)
+		if !ok {
+			return fmt.Errorf("%sthere's no path %s%s%s of %s. Type 'where' to see the paths from here%s", constants.ColorYellow, constants.ColorBrightRed, direction, constants.ColorYellow, cfg.CurrentLocation, constants.ColorReset)
+		}
+		return travelToLocation(cfg, next, nil)
+	}
+
+	if !regionMap.Adjacent(cfg.CurrentLocation, target) {
+		return fmt.Errorf("%s%s%s%s isn't next to %s. Type 'where' to see the paths from here, or 'travel %s' to find a route%s", constants.ColorBrightRed, target, constants.ColorReset, constants.ColorYellow, cfg.CurrentLocation, target, constants.ColorReset)
+	}
+	return travelToLocation(cfg, target, nil)
+}
+
+// commandWhere shows the player's region and location, and the paths leading away.
+func commandWhere(cfg *Config, args ...string) error {
+	if cfg.CurrentLocation == "" {
+		if cfg.CurrentRegion == "" {
+			return errNoLocation()
+		}
+		fmt.Printf("\n%sYou're in %s%s%s, but not at any location yet.%s\n", constants.ColorCyan, constants.ColorYellow, cfg.CurrentRegion, constants.ColorCyan, constants.ColorReset)
+		fmt.Printf("%sType 'travel %s' to list its locations.%s\n", constants.ColorGray, cfg.CurrentRegion, constants.ColorReset)
+		return nil
+	}
+
+	fmt.Printf("\n%sYou are at %s%s%s", constants.ColorCyan, constants.ColorYellow, cfg.CurrentLocation, constants.ColorCyan)
+	if cfg.CurrentRegion != "" {
+		fmt.Printf(" in %s%s%s", constants.ColorYellow, cfg.CurrentRegion, constants.ColorCyan)
+	}
+	fmt.Printf(".%s\n", constants.ColorReset)
+	if !printExits(cfg) {
+		fmt.Printf("%sThere's no map of the paths around here; use 'travel <location>' to go somewhere else.%s\n", constants.ColorGray, constants.ColorReset)
+		return nil
+	}
+	fmt.Printf("%sType 'move <direction>' to follow a path, or 'travel <location>' to walk further.%s\n", constants.ColorGray, constants.ColorReset)
+	return nil
+}
+
+// printExits lists the paths leaving the player's location, and reports whether the
+// location is on a map.
+func printExits(cfg *Config) bool {
+	regionMap, ok := world.Lookup(cfg.CurrentRegion)
+	if !ok || !regionMap.Has(cfg.CurrentLocation) {
+		return false
+	}
+	fmt.Printf("%sPaths:%s\n", constants.ColorCyan, constants.ColorReset)
+	for _, exit := range regionMap.Exits(cfg.CurrentLocation) {
+		fmt.Printf("  %s%-5s%s -> %s%s%s\n", constants.ColorYellow, exit.Direction, constants.ColorReset, constants.ColorWhite, exit.To, constants.ColorReset)
+	}
+	return true
+}
+
+// currentMap returns the map of the player's region, provided the player stands on it.
+func currentMap(cfg *Config) (*world.Map, error) {
+	if cfg.CurrentLocation == "" {
+		return nil, errNoLocation()
+	}
+	regionMap, ok := world.Lookup(cfg.CurrentRegion)
+	if !ok || !regionMap.Has(cfg.CurrentLocation) {
+		return nil, fmt.Errorf("%sthere's no map of the paths around %s. Use 'travel <location>' to go somewhere else%s", constants.ColorYellow, cfg.CurrentLocation, constants.ColorReset)
+	}
+	return regionMap, nil
+}
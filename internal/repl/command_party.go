@@ -2,12 +2,19 @@ package repl
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/voidarchive/pokedex/internal/shared/constants"
 )
 
+// commandParty shows the party, or changes it: 'party swap <slot> <slot>',
+// 'party move <slot> <slot>', 'party remove <slot>' to put a Pokemon in the PC and
+// 'party lead <slot>'. The lead, in slot 1, fights when 'battle' names no Pokemon.
 func commandParty(cfg *Config, args ...string) error {
+	if len(args) > 0 {
+		return changeParty(cfg, args)
+	}
 	if len(cfg.Party) == 0 {
 		fmt.Printf("%sYour party is empty.%s\n", constants.ColorYellow, constants.ColorReset)
 		fmt.Printf("%sCaught Pokemon will be added to your party if there is space.%s\n", constants.ColorGray, constants.ColorReset)
@@ -15,13 +22,16 @@ func commandParty(cfg *Config, args ...string) error {
 	}
 
 	fmt.Printf("\n%sYour Party:%s\n", constants.ColorBrightCyan, constants.ColorReset)
-	for i, p := range cfg.Party {
+	for i, p := range partyPokemon(cfg) {
 		fmt.Printf("  %sSlot %d:%s %s%s%s (Lvl %s%d%s) - XP: %s%d%s/%s%d%s\n",
 			constants.ColorYellow, i+1, constants.ColorReset,
-			constants.ColorWhite, p.Name, constants.ColorReset,
+			constants.ColorWhite, pokemonLabel(p), constants.ColorReset,
 			constants.ColorBrightCyan, p.Level, constants.ColorReset,
 			constants.ColorBrightCyan, p.CurrentXP, constants.ColorReset,
 			constants.ColorCyan, p.XPToNextLevel, constants.ColorReset)
+		if i == 0 {
+			fmt.Printf("      %sLead: fights when 'battle' names no Pokemon%s\n", constants.ColorGray, constants.ColorReset)
+		}
 
 		var typeStrings []string
 		for _, t := range p.PokemonData.Types {
@@ -33,3 +43,36 @@ func commandParty(cfg *Config, args ...string) error {
 	fmt.Println()
 	return nil
 }
+
+// changeParty runs the subcommands of 'party', which pick party members by slot.
+func changeParty(cfg *Config, args []string) error {
+	usage := fmt.Errorf("%susage: party [swap <slot> <slot>|move <slot> <slot>|remove <slot>|lead <slot>]%s", constants.ColorYellow, constants.ColorReset)
+	want := map[string]int{"swap": 2, "move": 2, "remove": 1, "lead": 1}[args[0]]
+	if want == 0 || len(args)-1 != want {
+		return usage
+	}
+	slots := make([]int, want)
+	for i, arg := range args[1:] {
+		slot, err := strconv.Atoi(arg)
+		if err != nil || slot < 1 || slot > len(cfg.Party) {
+			return fmt.Errorf("%sthere's no Pokemon in slot %s%s%s of your party. Type 'party' to see the slots%s", constants.ColorYellow, constants.ColorBrightRed, arg, constants.ColorYellow, constants.ColorReset)
+		}
+		slots[i] = slot - 1
+	}
+
+	pokemon := cfg.Collection[cfg.Party[slots[0]]]
+	switch args[0] {
+	case "swap":
+		swapPartySlots(cfg, slots[0], slots[1])
+		fmt.Printf("%s%s%s and %s%s%s swapped places.%s\n", constants.ColorYellow, pokemon.DisplayName(), constants.ColorCyan, constants.ColorYellow, cfg.Collection[cfg.Party[slots[0]]].DisplayName(), constants.ColorCyan, constants.ColorReset)
+	case "move":
+		movePartySlot(cfg, slots[0], slots[1])
+		fmt.Printf("%s%s%s is now in slot %d.%s\n", constants.ColorYellow, pokemon.DisplayName(), constants.ColorCyan, slots[1]+1, constants.ColorReset)
+	case "remove":
+		return moveToBox(cfg, pokemon, freeBox(cfg))
+	case "lead":
+		movePartySlot(cfg, slots[0], 0)
+		fmt.Printf("%s%s%s leads your party.%s\n", constants.ColorYellow, pokemon.DisplayName(), constants.ColorCyan, constants.ColorReset)
+	}
+	return nil
+}
@@ -1,22 +1,166 @@
 package repl
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/voidarchive/pokedex/internal/pokeapi"
 	"github.com/voidarchive/pokedex/internal/shared/constants"
 )
 
+// commandPokedex lists the species the player has seen and caught, in the order of the
+// national Pokedex or of a regional one, e.g. 'pokedex kanto'. 'pokedex stats' shows
+// how complete the Pokedex is for each region and generation.
 func commandPokedex(cfg *Config, args ...string) error {
-	if len(cfg.Pokedex) == 0 {
-		fmt.Printf("%sYour Pokedex is empty. Go catch some Pokemon!%s\n", constants.ColorYellow, constants.ColorReset)
+	if len(args) > 1 {
+		return fmt.Errorf("%susage: pokedex [stats|<pokedex_or_region>]%s", constants.ColorYellow, constants.ColorReset)
+	}
+	if len(args) == 1 && args[0] == "stats" {
+		return printDexCompletion(cfg)
+	}
+	if len(cfg.Dex.Seen) == 0 {
+		fmt.Printf("%sYour Pokedex is empty. Go explore and find some Pokemon!%s\n", constants.ColorYellow, constants.ColorReset)
 		return nil
 	}
 
-	fmt.Printf("\n%sYour Pokedex:%s\n", constants.ColorBrightCyan, constants.ColorReset)
-	for name := range cfg.Pokedex {
-		// Could add more info here like Level if desired, similar to party view
-		fmt.Printf("  %s- %s%s%s\n", constants.ColorGray, constants.ColorWhite, name, constants.ColorReset)
+	name := pokeapi.NationalPokedex
+	if len(args) == 1 {
+		name = args[0]
+	}
+	pokedex, err := fetchDex(cfg, name)
+	if err != nil {
+		return err
+	}
+
+	seen, caught := dexCounts(cfg.Dex, pokedex)
+	fmt.Printf("\n%s%s Pokedex:%s\n", constants.ColorBrightCyan, dexTitle(pokedex), constants.ColorReset)
+	for _, entry := range pokedex.PokemonEntries {
+		species := entry.PokemonSpecies.Name
+		switch {
+		case cfg.Dex.Caught[species]:
+			fmt.Printf("  %s#%03d %s%-14s %scaught%s\n", constants.ColorGray, entry.EntryNumber, constants.ColorWhite, species, constants.ColorBrightGreen, constants.ColorReset)
+		case cfg.Dex.Seen[species]:
+			fmt.Printf("  %s#%03d %s%-14s %sseen%s\n", constants.ColorGray, entry.EntryNumber, constants.ColorWhite, species, constants.ColorGray, constants.ColorReset)
+		}
+	}
+	if seen == 0 {
+		fmt.Printf("  %sYou haven't seen any Pokemon of this Pokedex yet.%s\n", constants.ColorGray, constants.ColorReset)
+	}
+	fmt.Printf("%sSeen: %s%d%s  Caught: %s%d%s of %d%s\n\n", constants.ColorCyan, constants.ColorYellow, seen, constants.ColorCyan, constants.ColorYellow, caught, constants.ColorCyan, len(pokedex.PokemonEntries), constants.ColorReset)
+	return nil
+}
+
+// fetchDex fetches a Pokedex by name. A region's name stands for its original
+// Pokedex, so 'pokedex sinnoh' shows the "original-sinnoh" Pokedex.
+func fetchDex(cfg *Config, name string) (pokeapi.Pokedex, error) {
+	pokedex, err := cfg.PokeapiClient.FetchPokedex(name)
+	if err == nil {
+		return pokedex, nil
+	}
+	if !errors.Is(err, pokeapi.ErrNotFound) {
+		return pokeapi.Pokedex{}, explainAPIError(err, "")
+	}
+
+	unknown := fmt.Sprintf("there's no Pokedex or region called '%s%s%s'. Try 'national' or a region, e.g. 'kanto'", constants.ColorBrightRed, name, constants.ColorYellow)
+	region, regionErr := cfg.PokeapiClient.FetchRegion(name)
+	if regionErr != nil {
+		return pokeapi.Pokedex{}, explainAPIError(regionErr, unknown)
+	}
+	if len(region.Pokedexes) == 0 {
+		return pokeapi.Pokedex{}, fmt.Errorf("%sthe %s%s%s region has no Pokedex of its own%s", constants.ColorYellow, constants.ColorBrightRed, name, constants.ColorYellow, constants.ColorReset)
+	}
+	pokedex, err = cfg.PokeapiClient.FetchPokedex(region.Pokedexes[0].Name)
+	if err != nil {
+		return pokeapi.Pokedex{}, explainAPIError(err, unknown)
+	}
+	return pokedex, nil
+}
+
+// dexTitle names a Pokedex in headings, e.g. "National" or "kanto".
+func dexTitle(pokedex pokeapi.Pokedex) string {
+	if pokedex.Name == pokeapi.NationalPokedex {
+		return "National"
+	}
+	return pokedex.Name
+}
+
+// dexCounts counts the species of pokedex the player has seen and caught.
+func dexCounts(dex Dex, pokedex pokeapi.Pokedex) (seen, caught int) {
+	for _, entry := range pokedex.PokemonEntries {
+		if dex.Seen[entry.PokemonSpecies.Name] {
+			seen++
+		}
+		if dex.Caught[entry.PokemonSpecies.Name] {
+			caught++
+		}
+	}
+	return seen, caught
+}
+
+// printDexCompletion shows how many species of each region's Pokedex and of each
+// generation the player has caught. Regions without a Pokedex or a generation of
+// their own are left out.
+func printDexCompletion(cfg *Config) error {
+	regions, err := fetchRegions(cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%sPokedex completion:%s\n", constants.ColorBrightCyan, constants.ColorReset)
+	fmt.Printf("  %sSeen %s%d%s species, caught %s%d%s.%s\n", constants.ColorCyan, constants.ColorYellow, len(cfg.Dex.Seen), constants.ColorCyan, constants.ColorYellow, len(cfg.Dex.Caught), constants.ColorCyan, constants.ColorReset)
+
+	var generations []pokeapi.Generation
+	fmt.Printf("\n%sBy region:%s\n", constants.ColorCyan, constants.ColorReset)
+	for _, r := range regions {
+		region, err := cfg.PokeapiClient.FetchRegion(r.Name)
+		if err != nil {
+			return explainAPIError(err, "")
+		}
+		if len(region.Pokedexes) > 0 {
+			pokedex, err := cfg.PokeapiClient.FetchPokedex(region.Pokedexes[0].Name)
+			if err != nil && !errors.Is(err, pokeapi.ErrNotFound) {
+				return explainAPIError(err, "")
+			}
+			if err == nil {
+				_, caught := dexCounts(cfg.Dex, pokedex)
+				printCompletion(region.Name, caught, len(pokedex.PokemonEntries))
+			}
+		}
+		if region.MainGeneration != nil {
+			generation, err := cfg.PokeapiClient.FetchGeneration(region.MainGeneration.Name)
+			if err != nil && !errors.Is(err, pokeapi.ErrNotFound) {
+				return explainAPIError(err, "")
+			}
+			if err == nil {
+				generations = append(generations, generation)
+			}
+		}
+	}
+
+	fmt.Printf("\n%sBy generation:%s\n", constants.ColorCyan, constants.ColorReset)
+	for _, generation := range generations {
+		caught := 0
+		for _, species := range generation.PokemonSpecies {
+			if cfg.Dex.Caught[species.Name] {
+				caught++
+			}
+		}
+		printCompletion(generation.Name, caught, len(generation.PokemonSpecies))
 	}
 	fmt.Println()
 	return nil
 }
+
+// printCompletion prints one line of the completion table, e.g.
+// "kanto            12/151   7.9%".
+func printCompletion(name string, caught, total int) {
+	percent := 0.0
+	if total > 0 {
+		percent = 100 * float64(caught) / float64(total)
+	}
+	color := constants.ColorWhite
+	if total > 0 && caught == total {
+		color = constants.ColorBrightGreen
+	}
+	fmt.Printf("  %s%-16s %s%4d%s/%-4d %5.1f%%%s\n", constants.ColorWhite, name, color, caught, constants.ColorGray, total, percent, constants.ColorReset)
+}
@@ -0,0 +1,93 @@
+package repl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/shared/constants"
+)
+
+// pokemonColumns are the columns of the 'pokemon' table. Right-aligned columns hold numbers.
+var pokemonColumns = []struct {
+	title string
+	right bool
+	value func(p pokeapi.UserPokemon) string
+}{
+	{"ID", true, func(p pokeapi.UserPokemon) string { return "#" + strconv.Itoa(p.UID) }},
+	{"Name", false, func(p pokeapi.UserPokemon) string { return p.DisplayName() }},
+	{"Species", false, func(p pokeapi.UserPokemon) string { return p.Name }},
+	{"Lvl", true, func(p pokeapi.UserPokemon) string { return strconv.Itoa(p.Level) }},
+	{"Type", false, func(p pokeapi.UserPokemon) string { return strings.Join(typeNames(p.PokemonData), "/") }},
+	{"HP", true, statColumn("hp")},
+	{"Atk", true, statColumn("attack")},
+	{"Def", true, statColumn("defense")},
+	{"SpA", true, statColumn("special-attack")},
+	{"SpD", true, statColumn("special-defense")},
+	{"Spe", true, statColumn("speed")},
+	{"Caught", false, caughtDate},
+}
+
+func statColumn(stat string) func(p pokeapi.UserPokemon) string {
+	return func(p pokeapi.UserPokemon) string { return strconv.Itoa(numberField(p, "stat."+stat)) }
+}
+
+// commandPokemon lists the Pokemon the player owns as a table. Filters and a sort order
+// pick and order them, e.g. 'pokemon type:fire level>10 --sort level desc'; see query.go.
+func commandPokemon(cfg *Config, args ...string) error {
+	query, err := parseQuery(args)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Collection) == 0 {
+		fmt.Printf("%sYou don't have any Pokemon yet. Go catch some!%s\n", constants.ColorYellow, constants.ColorReset)
+		return nil
+	}
+
+	results := query.run(ownedPokemon(cfg))
+	if len(results) == 0 {
+		fmt.Printf("%sNone of your Pokemon match '%s'.%s\n", constants.ColorYellow, strings.Join(args, " "), constants.ColorReset)
+		return nil
+	}
+	fmt.Println()
+	printPokemonTable(results)
+	fmt.Printf("%s%d of %d Pokemon%s\n\n", constants.ColorGray, len(results), len(cfg.Collection), constants.ColorReset)
+	return nil
+}
+
+// printPokemonTable prints one row per Pokemon, with every column as wide as its
+// widest cell.
+func printPokemonTable(pokemon []pokeapi.UserPokemon) {
+	cells := make([][]string, len(pokemon))
+	widths := make([]int, len(pokemonColumns))
+	for i, column := range pokemonColumns {
+		widths[i] = len(column.title)
+	}
+	for row, p := range pokemon {
+		cells[row] = make([]string, len(pokemonColumns))
+		for i, column := range pokemonColumns {
+			cells[row][i] = column.value(p)
+			widths[i] = max(widths[i], len(cells[row][i]))
+		}
+	}
+
+	pad := func(i int, text string) string {
+		if pokemonColumns[i].right {
+			return fmt.Sprintf("%*s", widths[i], text)
+		}
+		return fmt.Sprintf("%-*s", widths[i], text)
+	}
+	header := make([]string, len(pokemonColumns))
+	for i, column := range pokemonColumns {
+		header[i] = pad(i, column.title)
+	}
+	fmt.Printf("  %s%s%s\n", constants.ColorCyan, strings.TrimRight(strings.Join(header, "  "), " "), constants.ColorReset)
+	for _, row := range cells {
+		line := make([]string, len(row))
+		for i, cell := range row {
+			line[i] = pad(i, cell)
+		}
+		fmt.Printf("  %s%s%s\n", constants.ColorWhite, strings.TrimRight(strings.Join(line, "  "), " "), constants.ColorReset)
+	}
+}
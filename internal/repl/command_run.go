@@ -0,0 +1,17 @@
+package repl
+
+import (
+	"fmt"
+
+	"github.com/voidarchive/pokedex/internal/shared/constants"
+)
+
+// commandRun ends the current wild encounter.
+func commandRun(cfg *Config, args ...string) error {
+	if cfg.Encounter == nil {
+		return errNoEncounter("run from")
+	}
+	fmt.Printf("%sGot away safely from the wild %s%s%s!%s\n", constants.ColorGreen, constants.ColorYellow, cfg.Encounter.Pokemon.Name, constants.ColorGreen, constants.ColorReset)
+	cfg.Encounter = nil
+	return nil
+}
@@ -0,0 +1,48 @@
+package repl
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/voidarchive/pokedex/internal/shared/constants"
+)
+
+// commandTime shows the in-game time, or switches the clock: 'time set <HH:MM>' starts
+// a simulated clock at that time of the current day, and 'time wall' goes back to the
+// computer's clock.
+func commandTime(cfg *Config, args ...string) error {
+	if cfg.Clock == nil {
+		cfg.Clock = NewWallClock()
+	}
+
+	switch {
+	case len(args) == 0:
+	case args[0] == "wall" && len(args) == 1:
+		cfg.Clock = NewWallClock()
+	case args[0] == "set" && len(args) == 2:
+		clockTime, err := time.Parse("15:04", args[1])
+		if err != nil {
+			return fmt.Errorf("%sinvalid time: %s%s%s. Use 24-hour HH:MM, e.g. 'time set 21:30'%s", constants.ColorYellow, constants.ColorBrightRed, args[1], constants.ColorYellow, constants.ColorReset)
+		}
+		today := cfg.Clock.Now()
+		start := time.Date(today.Year(), today.Month(), today.Day(), clockTime.Hour(), clockTime.Minute(), 0, 0, today.Location())
+		cfg.Clock = NewSimulatedClock(start)
+	default:
+		return fmt.Errorf("%susage: time [set <HH:MM>|wall]%s", constants.ColorYellow, constants.ColorReset)
+	}
+
+	now := cfg.Clock.Now()
+	fmt.Printf("%sIt's %s%s%s (%s).%s\n", constants.ColorCyan, constants.ColorYellow, now.Format("15:04"), constants.ColorCyan, timeOfDay(now), constants.ColorReset)
+	if cfg.Clock.Simulated() {
+		fmt.Printf("%sThe clock is simulated: it moves %d minutes with every action. Type 'time wall' to follow your computer's clock.%s\n", constants.ColorGray, int(cfg.Clock.Step.Minutes()), constants.ColorReset)
+	} else {
+		fmt.Printf("%sThe clock follows your computer's clock. Type 'time set <HH:MM>' to simulate one.%s\n", constants.ColorGray, constants.ColorReset)
+	}
+	return nil
+}
+
+// prompt is the REPL prompt, showing the in-game time.
+func prompt(cfg *Config) string {
+	now := cfg.now()
+	return fmt.Sprintf("%sPokedex %s[%s %s]%s > %s", constants.ColorCyan, constants.ColorGray, now.Format("15:04"), timeOfDay(now), constants.ColorCyan, constants.ColorReset)
+}
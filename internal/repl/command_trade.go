@@ -0,0 +1,56 @@
+package repl
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/shared/constants"
+)
+
+// commandTrade link-trades a party Pokemon with a partner who trades it straight back,
+// which drives the "trade" evolutions, e.g. 'trade kadabra' or 'trade karrablast for
+// shelmet' for the ones that need a particular species in exchange. Like in the games,
+// a trade evolution can't be cancelled, but an Everstone stops it.
+func commandTrade(cfg *Config, args ...string) error {
+	if len(args) != 1 && (len(args) != 3 || args[1] != "for") {
+		return fmt.Errorf("%susage: trade <pokemon_name> [for <species>]%s", constants.ColorYellow, constants.ColorReset)
+	}
+	pokemon, err := resolvePartyPokemon(cfg, args[0], "trade")
+	if err != nil {
+		return err
+	}
+
+	partner := "a Pokemon"
+	tradeSpecies := ""
+	if len(args) == 3 {
+		species, err := cfg.PokeapiClient.FetchPokemonSpecies(args[2])
+		if errors.Is(err, pokeapi.ErrNotFound) {
+			return fmt.Errorf("%sthere's no Pokemon species called '%s%s%s' to trade for%s", constants.ColorYellow, constants.ColorBrightRed, args[2], constants.ColorYellow, constants.ColorReset)
+		}
+		if err != nil {
+			return explainAPIError(err, "")
+		}
+		tradeSpecies = species.Name
+		partner = "their " + species.Name
+		cfg.Dex.markSeen(species.Name)
+	}
+
+	fmt.Printf("%sYou sent %s%s%s to your link partner for %s%s%s, and they traded it back.%s\n", constants.ColorCyan, constants.ColorYellow, pokemon.DisplayName(), constants.ColorCyan, constants.ColorYellow, partner, constants.ColorCyan, constants.ColorReset)
+	evolution, detail, found, err := findEvolution(cfg, pokemon, "trade", "", tradeSpecies)
+	if err != nil {
+		return err
+	}
+	if !found {
+		fmt.Printf("  %s%s%s came back just as it was.%s\n", constants.ColorGray, constants.ColorYellow, pokemon.DisplayName(), constants.ColorReset)
+		return nil
+	}
+	if pokemon.HeldItem == everstone {
+		fmt.Printf("  %s%s%s could evolve into %s, but its Everstone keeps it from evolving.%s\n", constants.ColorGray, constants.ColorYellow, pokemon.DisplayName(), evolution, constants.ColorReset)
+		return nil
+	}
+
+	fmt.Printf("%sWhat? %s%s%s is evolving!%s\n", constants.ColorBrightPurple, constants.ColorYellow, pokemon.DisplayName(), constants.ColorBrightPurple, constants.ColorReset)
+	_, err = performEvolution(cfg, pokemon, evolution, detail)
+	return err
+}
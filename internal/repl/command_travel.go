@@ -0,0 +1,208 @@
+package repl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/shared/constants"
+	"github.com/voidarchive/pokedex/internal/world"
+)
+
+// commandRegions lists every region, marking the one the player is in.
+func commandRegions(cfg *Config, args ...string) error {
+	regions, err := fetchRegions(cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%sRegions:%s\n", constants.ColorCyan, constants.ColorReset)
+	for _, region := range regions {
+		marker := ""
+		if region.Name == cfg.CurrentRegion {
+			marker = fmt.Sprintf(" %s(you are here)%s", constants.ColorGreen, constants.ColorReset)
+		}
+		fmt.Printf("  %s- %s%s%s%s\n", constants.ColorGray, constants.ColorWhite, region.Name, constants.ColorReset, marker)
+	}
+	fmt.Printf("\n%sType 'travel <region>' to go to a region.%s\n", constants.ColorGray, constants.ColorReset)
+	return nil
+}
+
+// fetchRegions returns every region PokeAPI knows, from all pages of its region list.
+func fetchRegions(cfg *Config) ([]pokeapi.NamedAPIResource, error) {
+	var regions []pokeapi.NamedAPIResource
+	for region, err := range cfg.PokeapiClient.All(context.Background(), "region", pokeapi.DefaultPageLimit) {
+		if err != nil {
+			return nil, explainAPIError(err, "PokeAPI has no region list")
+		}
+		regions = append(regions, region)
+	}
+	return regions, nil
+}
+
+// commandTravel moves the player to a region, or to a location: by name, or by its
+// number in the list shown by the last 'travel <region>'. Within a region's map the
+// player walks the shortest route there.
+func commandTravel(cfg *Config, args ...string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%susage: travel <region|location|number>%s", constants.ColorYellow, constants.ColorReset)
+	}
+	destination := args[0]
+
+	if number, err := strconv.Atoi(destination); err == nil {
+		if number < 1 || number > len(cfg.LocationChoices) {
+			return fmt.Errorf("%sinvalid location number: %s%d%s. Run 'travel <region>' to list the locations of a region%s", constants.ColorYellow, constants.ColorBrightRed, number, constants.ColorYellow, constants.ColorReset)
+		}
+		return journeyTo(cfg, cfg.LocationChoices[number-1].Name)
+	}
+
+	region, err := cfg.PokeapiClient.FetchRegion(destination)
+	if err == nil {
+		return travelToRegion(cfg, region)
+	}
+	if !errors.Is(err, pokeapi.ErrNotFound) {
+		return explainAPIError(err, "")
+	}
+	return journeyTo(cfg, destination)
+}
+
+// journeyTo travels to the named location in the player's region. In a region with a
+// map the location has to be on it, and the player walks the shortest route there;
+// only regions without a map let the player go straight there. Locations of other
+// regions are reached with 'travel <region>' first.
+func journeyTo(cfg *Config, locationName string) error {
+	if locationName == cfg.CurrentLocation {
+		return fmt.Errorf("%syou're already at %s%s%s%s", constants.ColorYellow, constants.ColorBrightRed, locationName, constants.ColorYellow, constants.ColorReset)
+	}
+	location, err := cfg.PokeapiClient.FetchLocation(locationName)
+	if err != nil {
+		return explainAPIError(err, fmt.Sprintf("there's no region or location called '%s%s%s'. Run 'regions' to list the regions", constants.ColorBrightRed, locationName, constants.ColorYellow))
+	}
+	if regionName := location.RegionName(); regionName != "" && regionName != cfg.CurrentRegion {
+		return fmt.Errorf("%s%s%s%s is in %s. Run 'travel %s' to go there first%s", constants.ColorBrightRed, location.Name, constants.ColorReset, constants.ColorYellow, regionName, regionName, constants.ColorReset)
+	}
+
+	var route []string
+	if regionMap, ok := world.Lookup(cfg.CurrentRegion); ok {
+		if !regionMap.Has(location.Name) {
+			return fmt.Errorf("%s%s%s%s isn't on the map of %s. Run 'travel %s' to list the locations you can go to%s", constants.ColorBrightRed, location.Name, constants.ColorReset, constants.ColorYellow, cfg.CurrentRegion, cfg.CurrentRegion, constants.ColorReset)
+		}
+		if regionMap.Has(cfg.CurrentLocation) {
+			route, ok = regionMap.Route(cfg.CurrentLocation, location.Name)
+			if !ok {
+				return fmt.Errorf("%sthere's no route from %s to %s%s%s%s", constants.ColorYellow, cfg.CurrentLocation, constants.ColorBrightRed, location.Name, constants.ColorYellow, constants.ColorReset)
+			}
+		}
+	}
+	return travelToLocation(cfg, location.Name, route)
+}
+
+// travelToRegion lists the locations of region as choices for 'travel <number>'. A
+// player coming from elsewhere moves into the region: to the start of its map, or
+// without a location in a region that has no map. Only the locations on a region's
+// map are listed.
+func travelToRegion(cfg *Config, region pokeapi.Region) error {
+	regionMap, mapped := world.Lookup(region.Name)
+	cfg.LocationChoices = region.Locations
+	if mapped {
+		cfg.LocationChoices = nil
+		for _, location := range region.Locations {
+			if regionMap.Has(location.Name) {
+				cfg.LocationChoices = append(cfg.LocationChoices, location)
+			}
+		}
+	}
+
+	arriving := region.Name != cfg.CurrentRegion
+	if arriving {
+		fmt.Printf("\n%sWelcome to %s%s%s!%s\n", constants.ColorBrightCyan, constants.ColorYellow, region.Name, constants.ColorBrightCyan, constants.ColorReset)
+	}
+	fmt.Printf("%sLocations:%s\n", constants.ColorCyan, constants.ColorReset)
+	for i, location := range cfg.LocationChoices {
+		fmt.Printf("  %s%d%s: %s%s%s\n", constants.ColorYellow, i+1, constants.ColorReset, constants.ColorWhite, location.Name, constants.ColorReset)
+	}
+	fmt.Printf("\n%sType 'travel <number>' or 'travel <location>' to go to a location.%s\n", constants.ColorGray, constants.ColorReset)
+	if !arriving {
+		return nil
+	}
+
+	leaveEncounter(cfg)
+	cfg.CurrentRegion = region.Name
+	cfg.CurrentLocation = ""
+	cfg.CurrentAreaChoices = nil
+	if mapped && regionMap.Start != "" {
+		return travelToLocation(cfg, regionMap.Start, nil)
+	}
+	return nil
+}
+
+// travelToLocation moves the player to the named location, and into its region. A
+// route with stops along the way is shown before arriving.
+func travelToLocation(cfg *Config, locationName string, route []string) error {
+	location, err := cfg.PokeapiClient.FetchLocation(locationName)
+	if err != nil {
+		return explainAPIError(err, fmt.Sprintf("there's no region or location called '%s%s%s'. Run 'regions' to list the regions", constants.ColorBrightRed, locationName, constants.ColorYellow))
+	}
+
+	leaveEncounter(cfg)
+	walkParty(cfg, max(len(route)-1, 1))
+	cfg.CurrentLocation = location.Name
+	cfg.CurrentAreaChoices = nil
+	if regionName := location.RegionName(); regionName != "" {
+		cfg.CurrentRegion = regionName
+	}
+
+	fmt.Println()
+	if len(route) > 2 {
+		fmt.Printf("%sRoute (%d steps): %s%s\n", constants.ColorCyan, len(route)-1, strings.Join(route, " -> "), constants.ColorReset)
+	}
+	fmt.Printf("%sYou arrived at %s%s%s", constants.ColorBrightCyan, constants.ColorYellow, location.Name, constants.ColorBrightCyan)
+	if cfg.CurrentRegion != "" {
+		fmt.Printf(" in %s%s%s", constants.ColorYellow, cfg.CurrentRegion, constants.ColorBrightCyan)
+	}
+	fmt.Printf(".%s\n", constants.ColorReset)
+	printExits(cfg)
+	fmt.Printf("%sType 'areas' to see where you can look for Pokemon here.%s\n", constants.ColorGray, constants.ColorReset)
+	return nil
+}
+
+// commandAreas lists the areas of the player's current location as choices for 'explore'.
+func commandAreas(cfg *Config, args ...string) error {
+	if cfg.CurrentLocation == "" {
+		return errNoLocation()
+	}
+	location, err := cfg.PokeapiClient.FetchLocation(cfg.CurrentLocation)
+	if err != nil {
+		return explainAPIError(err, fmt.Sprintf("there's no location called '%s%s%s' anymore. Use 'travel' to go somewhere else", constants.ColorBrightRed, cfg.CurrentLocation, constants.ColorYellow))
+	}
+
+	fmt.Printf("\n%sAreas of %s%s%s:%s\n", constants.ColorCyan, constants.ColorYellow, location.Name, constants.ColorCyan, constants.ColorReset)
+	if len(location.Areas) == 0 {
+		cfg.CurrentAreaChoices = nil
+		fmt.Printf("  %sThere are no wild Pokemon around %s.%s\n", constants.ColorGray, location.Name, constants.ColorReset)
+		return nil
+	}
+	cfg.CurrentAreaChoices = make([]pokeapi.LocationArea, len(location.Areas))
+	for i, area := range location.Areas {
+		cfg.CurrentAreaChoices[i] = pokeapi.LocationArea{Name: area.Name, URL: area.URL}
+		fmt.Printf("  %s%d%s: %s%s%s\n", constants.ColorYellow, i+1, constants.ColorReset, constants.ColorWhite, area.Name, constants.ColorReset)
+	}
+	fmt.Printf("\n%sType 'explore <number>' or 'explore <full_area_name>' to look for Pokemon in an area.%s\n", constants.ColorGray, constants.ColorReset)
+	return nil
+}
+
+// leaveEncounter ends the current encounter, if any, when the player moves away.
+func leaveEncounter(cfg *Config) {
+	if cfg.Encounter != nil {
+		fmt.Printf("%sYou left the wild %s behind.%s\n", constants.ColorGray, cfg.Encounter.Pokemon.Name, constants.ColorReset)
+		cfg.Encounter = nil
+	}
+}
+
+// errNoLocation is returned by commands that need the player to be at a location.
+func errNoLocation() error {
+	return fmt.Errorf("%syou haven't traveled to a location yet. Run 'regions', then 'travel <region>' and 'travel <location>'%s", constants.ColorYellow, constants.ColorReset)
+}
@@ -0,0 +1,440 @@
+package repl
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/pokeapi/pokeapitest"
+	"github.com/voidarchive/pokedex/internal/pokecache"
+)
+
+// newCanalaveConfig builds a Config whose client serves Canalave City, an area with only
+// water encounters, and whose randomizer is seeded, so commands behave the same on every run.
+func newCanalaveConfig(t *testing.T, seed int64) *Config {
+	t.Helper()
+	first := pokeapi.BaseURL + "/location-area"
+	next := pokeapi.BaseURL + "/location-area?offset=20&limit=20"
+	fake := pokeapitest.NewFake().
+		AddLocationAreaPage(first, pokeapi.LocationAreasResponse{
+			Next: &next,
+			Results: []pokeapi.LocationArea{
+				{Name: "canalave-city-area", URL: pokeapi.BaseURL + "/location-area/canalave-city-area/"},
+				{Name: "eterna-city-area", URL: pokeapi.BaseURL + "/location-area/eterna-city-area/"},
+				{Name: "pastoria-city-area", URL: pokeapi.BaseURL + "/location-area/pastoria-city-area/"},
+			},
+		}).
+		AddRegion(pokeapi.Region{Name: "sinnoh"}, pokeapitest.Location("canalave-city", "canalave-city-area")).
+		AddLocationArea(pokeapitest.Area("canalave-city-area",
+			pokeapitest.EncounterBy("tentacool", "surf", 60, 20, 30),
+			pokeapitest.EncounterBy("wingull", "surf", 30, 20, 30),
+			pokeapitest.EncounterBy("tentacruel", "surf", 5, 20, 40),
+			pokeapitest.EncounterBy("magikarp", "old-rod", 100, 3, 10),
+			pokeapitest.EncounterBy("staryu", "good-rod", 40, 15, 25),
+		)).
+		AddPokemon(
+			pokeapitest.NewPokemon(25, "pikachu", 112, pokeapitest.Stats{HP: 35, Attack: 55, Defense: 40, SpecialAttack: 50, SpecialDefense: 50, Speed: 90}, "electric"),
+			pokeapitest.NewPokemon(26, "raichu", 243, pokeapitest.Stats{HP: 60, Attack: 90, Defense: 55, SpecialAttack: 90, SpecialDefense: 80, Speed: 110}, "electric"),
+			pokeapitest.NewPokemon(72, "tentacool", 67, pokeapitest.Stats{HP: 40, Attack: 40, Defense: 35, SpecialAttack: 50, SpecialDefense: 100, Speed: 70}, "water", "poison"),
+			pokeapitest.NewPokemon(73, "tentacruel", 180, pokeapitest.Stats{HP: 80, Attack: 70, Defense: 65, SpecialAttack: 80, SpecialDefense: 120, Speed: 100}, "water", "poison"),
+			pokeapitest.NewPokemon(120, "staryu", 68, pokeapitest.Stats{HP: 30, Attack: 45, Defense: 55, SpecialAttack: 70, SpecialDefense: 55, Speed: 85}, "water"),
+			pokeapitest.NewPokemon(129, "magikarp", 40, pokeapitest.Stats{HP: 20, Attack: 10, Defense: 55, SpecialAttack: 15, SpecialDefense: 20, Speed: 80}, "water"),
+			pokeapitest.NewPokemon(278, "wingull", 54, pokeapitest.Stats{HP: 40, Attack: 30, Defense: 30, SpecialAttack: 55, SpecialDefense: 30, Speed: 85}, "water", "flying"),
+		).
+		AddSpecies(pokeapi.PokemonSpecies{ID: 25, Name: "pikachu", CaptureRate: 190})
+	return NewConfig(fake, pokecache.NewCache(5*time.Minute), rand.New(rand.NewSource(seed)))
+}
+
+func TestCommandMapThenExploreByNumber(t *testing.T) {
+	cfg := newCanalaveConfig(t, 1)
+
+	if err := commandMapf(cfg); err != nil {
+		t.Fatalf("map returned error: %v", err)
+	}
+	if len(cfg.CurrentAreaChoices) != 3 {
+		t.Fatalf("expected 3 area choices, got %d", len(cfg.CurrentAreaChoices))
+	}
+	if cfg.PrevLocationAreaURL != nil {
+		t.Errorf("expected no previous page after the first map, got %s", *cfg.PrevLocationAreaURL)
+	}
+
+	// The first area on the first map page is in canalave-city.
+	cfg.CurrentLocation = "canalave-city"
+
+	if err := commandExplore(cfg, "1"); err != nil {
+		t.Fatalf("explore 1 returned error: %v", err)
+	}
+	if err := commandExplore(cfg, "canalave-city-area"); err != nil {
+		t.Fatalf("explore by name returned error: %v", err)
+	}
+	if err := commandExplore(cfg, "unknown-area"); err == nil {
+		t.Errorf("expected explore of an unknown area to fail")
+	}
+
+	if err := commandMapb(cfg); err == nil {
+		t.Errorf("expected mapb to fail on the first page")
+	}
+	if cfg.CurrentAreaChoices != nil {
+		t.Errorf("expected a failed mapb to clear the area choices")
+	}
+}
+
+// startEncounter puts the player in front of a wild Pokemon fetched through cfg's client.
+func startEncounter(t *testing.T, cfg *Config, name string, level int) {
+	t.Helper()
+	pokemon, err := cfg.PokeapiClient.FetchPokemon(name)
+	if err != nil {
+		t.Fatalf("could not fetch %s: %v", name, err)
+	}
+	cfg.Encounter = newEncounter(pokemon, level, "test-area")
+}
+
+func TestCommandExploreStartsEncounter(t *testing.T) {
+	cfg := newCanalaveConfig(t, 1)
+	cfg.CurrentLocation = "canalave-city"
+
+	// canalave-city-area only has water encounters, so there's nothing to meet on foot.
+	if err := commandExplore(cfg, "canalave-city-area"); err != nil {
+		t.Fatalf("explore returned error: %v", err)
+	}
+	if cfg.Encounter != nil {
+		t.Fatalf("expected no walking encounter in canalave-city-area, met %s", cfg.Encounter.Pokemon.Name)
+	}
+
+	if err := commandExplore(cfg, "canalave-city-area", "--method=old-rod"); err != nil {
+		t.Fatalf("explore --method=old-rod returned error: %v", err)
+	}
+	if cfg.Encounter == nil || cfg.Encounter.Pokemon.Name != "magikarp" {
+		t.Fatalf("expected the old rod to fish up magikarp, got %+v", cfg.Encounter)
+	}
+	if level := cfg.Encounter.Level; level < 3 || level > 10 {
+		t.Errorf("old-rod magikarp level %d is outside 3-10", level)
+	}
+	if cfg.Encounter.Wild.HP != cfg.Encounter.Wild.MaxHP || cfg.Encounter.Wild.MaxHP == 0 {
+		t.Errorf("expected the wild Pokemon at full HP, have %d/%d", cfg.Encounter.Wild.HP, cfg.Encounter.Wild.MaxHP)
+	}
+
+	surfers := map[string]bool{"tentacool": true, "tentacruel": true, "wingull": true}
+	for range 10 {
+		if err := commandExplore(cfg, "--method", "surf", "canalave-city-area"); err != nil {
+			t.Fatalf("explore --method surf returned error: %v", err)
+		}
+		if !surfers[cfg.Encounter.Pokemon.Name] {
+			t.Fatalf("%s can't be met while surfing in canalave-city-area", cfg.Encounter.Pokemon.Name)
+		}
+	}
+
+	if err := commandExplore(cfg, "canalave-city-area", "--method"); err == nil {
+		t.Errorf("expected --method without a value to fail")
+	}
+
+	if err := commandRun(cfg); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if cfg.Encounter != nil {
+		t.Errorf("expected run to end the encounter")
+	}
+}
+
+func TestCommandCatch(t *testing.T) {
+	cfg := newCanalaveConfig(t, 1)
+	startEncounter(t, cfg, "pikachu", 5)
+
+	// A Quick Ball on the first turn is a guaranteed catch for pikachu's capture rate.
+	if err := commandCatch(cfg, "pikachu", "quickball"); err != nil {
+		t.Fatalf("catch returned error: %v", err)
+	}
+	caught, ok := cfg.Collection[1]
+	if !ok || caught.Name != "pikachu" {
+		t.Fatalf("expected pikachu to be caught as #1, collection = %v", cfg.Collection)
+	}
+	if caught.Level != 5 || caught.XPToNextLevel != caught.CalculateNewXPToNextLevel() {
+		t.Errorf("unexpected new Pokemon progress: level=%d xpToNext=%d", caught.Level, caught.XPToNextLevel)
+	}
+	if len(cfg.Party) != 1 || cfg.Collection[cfg.Party[0]].Name != "pikachu" {
+		t.Errorf("expected pikachu to join the party, party = %v", cfg.Party)
+	}
+	if cfg.Inventory["quickball"] != 1 {
+		t.Errorf("expected one quickball to be used, have %d", cfg.Inventory["quickball"])
+	}
+	if cfg.Encounter != nil {
+		t.Errorf("expected the encounter to end once pikachu is caught")
+	}
+
+	if err := commandInspect(cfg, "pikachu"); err != nil {
+		t.Errorf("inspect returned error: %v", err)
+	}
+}
+
+func TestCommandCatchErrors(t *testing.T) {
+	cfg := newCanalaveConfig(t, 1)
+
+	if err := commandCatch(cfg); err == nil {
+		t.Errorf("expected catching outside an encounter to fail")
+	}
+
+	startEncounter(t, cfg, "pikachu", 5)
+	if err := commandCatch(cfg, "raichu"); err == nil {
+		t.Errorf("expected catching a Pokemon other than the wild one to fail")
+	}
+	if err := commandCatch(cfg, "pikachu", "masterball"); err == nil {
+		t.Errorf("expected an unknown ball type to fail")
+	}
+	cfg.Inventory["ultraball"] = 0
+	if err := commandCatch(cfg, "ultraball"); err == nil {
+		t.Errorf("expected catching without balls to fail")
+	}
+
+	if len(cfg.Collection) != 0 {
+		t.Errorf("expected nothing to be caught, collection = %v", cfg.Collection)
+	}
+	if cfg.Inventory["pokeball"] != 10 || cfg.Encounter.Turn != 0 {
+		t.Errorf("no ball should have been thrown: pokeballs=%d turn=%d", cfg.Inventory["pokeball"], cfg.Encounter.Turn)
+	}
+}
+
+func TestCommandTravelRegionLocationAndAreas(t *testing.T) {
+	fake := pokeapitest.NewFake().
+		AddRegion(pokeapi.Region{Name: "sinnoh"},
+			pokeapitest.Location("twinleaf-town"),
+			pokeapitest.Location("canalave-city", "canalave-city-area"),
+			pokeapitest.Location("eterna-city"),
+			pokeapitest.Location("great-marsh"),
+		).
+		AddLocationArea(pokeapitest.Area("canalave-city-area", pokeapitest.EncounterBy("tentacool", "old-rod", 100, 10, 15))).
+		AddPokemon(pokeapitest.NewPokemon(72, "tentacool", 67, pokeapitest.Stats{HP: 40, Attack: 40, Defense: 35, SpecialAttack: 50, SpecialDefense: 100, Speed: 70}, "water", "poison"))
+	cfg := NewConfig(fake, pokecache.NewCache(5*time.Minute), rand.New(rand.NewSource(1)))
+
+	if err := commandExplore(cfg, "canalave-city-area"); err == nil {
+		t.Errorf("expected explore to fail before traveling anywhere")
+	}
+	if err := commandRegions(cfg); err != nil {
+		t.Fatalf("regions returned error: %v", err)
+	}
+
+	if err := commandTravel(cfg, "sinnoh"); err != nil {
+		t.Fatalf("travel sinnoh returned error: %v", err)
+	}
+	if cfg.CurrentRegion != "sinnoh" || cfg.CurrentLocation != "twinleaf-town" {
+		t.Fatalf("after travel sinnoh: region=%q location=%q; want the start of its map, twinleaf-town", cfg.CurrentRegion, cfg.CurrentLocation)
+	}
+	// great-marsh isn't on the map of sinnoh.
+	if len(cfg.LocationChoices) != 3 {
+		t.Fatalf("location choices = %v; want the 3 locations on the map", cfg.LocationChoices)
+	}
+	if err := commandAreas(cfg); err != nil {
+		t.Errorf("areas returned error: %v", err)
+	}
+
+	if err := commandTravel(cfg, "2"); err != nil {
+		t.Fatalf("travel 2 returned error: %v", err)
+	}
+	if cfg.CurrentLocation != "canalave-city" {
+		t.Fatalf("travel 2 should go to canalave-city, at %q", cfg.CurrentLocation)
+	}
+	if err := commandAreas(cfg); err != nil {
+		t.Fatalf("areas returned error: %v", err)
+	}
+	if len(cfg.CurrentAreaChoices) != 1 || cfg.CurrentAreaChoices[0].Name != "canalave-city-area" {
+		t.Fatalf("area choices = %v; want [canalave-city-area]", cfg.CurrentAreaChoices)
+	}
+	if err := commandExplore(cfg, "1", "--method", "old-rod"); err != nil {
+		t.Fatalf("explore 1 returned error: %v", err)
+	}
+	if cfg.Encounter == nil {
+		t.Fatalf("expected an encounter in canalave-city-area")
+	}
+
+	if err := commandTravel(cfg, "eterna-city"); err != nil {
+		t.Fatalf("travel eterna-city returned error: %v", err)
+	}
+	if cfg.Encounter != nil {
+		t.Errorf("expected traveling to leave the encounter behind")
+	}
+	if err := commandExplore(cfg, "canalave-city-area"); err == nil {
+		t.Errorf("expected exploring an area of another location to fail")
+	}
+
+	if err := commandTravel(cfg, "atlantis"); err == nil {
+		t.Errorf("expected travel to an unknown place to fail")
+	}
+	if err := commandTravel(cfg, "great-marsh"); err == nil {
+		t.Errorf("expected travel to a location off the map to fail")
+	}
+	if err := commandTravel(cfg, "9"); err == nil {
+		t.Errorf("expected travel to an out of range number to fail")
+	}
+	if cfg.CurrentRegion != "sinnoh" || cfg.CurrentLocation != "eterna-city" {
+		t.Errorf("failed travels should not move the player: region=%q location=%q", cfg.CurrentRegion, cfg.CurrentLocation)
+	}
+}
+
+func TestSaveAndLoadKeepWhereaboutsAndClock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pokedex.json")
+	cfg := newCanalaveConfig(t, 1)
+	cfg.CurrentRegion = "sinnoh"
+	cfg.CurrentLocation = "eterna-city"
+	cfg.Clock = NewSimulatedClock(time.Date(2024, time.May, 1, 21, 30, 0, 0, time.UTC))
+	cfg.Dex.markSeen("wingull")
+	cfg.Dex.markCaught("pikachu")
+
+	if err := savePokedex(path, cfg); err != nil {
+		t.Fatalf("savePokedex returned error: %v", err)
+	}
+	saveData, err := loadPokedex(path)
+	if err != nil {
+		t.Fatalf("loadPokedex returned error: %v", err)
+	}
+
+	restored := newCanalaveConfig(t, 1)
+	saveData.apply(restored)
+	if restored.CurrentRegion != "sinnoh" || restored.CurrentLocation != "eterna-city" {
+		t.Errorf("restored region=%q location=%q; want sinnoh, eterna-city", restored.CurrentRegion, restored.CurrentLocation)
+	}
+	if !restored.Clock.Simulated() || restored.Clock.Now().Format("15:04") != "21:30" {
+		t.Errorf("restored clock = %s (simulated=%v); want a simulated 21:30", restored.Clock.Now().Format("15:04"), restored.Clock.Simulated())
+	}
+	if !restored.Dex.Seen["wingull"] || restored.Dex.Caught["wingull"] || !restored.Dex.Seen["pikachu"] || !restored.Dex.Caught["pikachu"] {
+		t.Errorf("restored Pokedex = %+v; want wingull seen and pikachu caught", restored.Dex)
+	}
+}
+
+func TestSaveAndLoadKeepTheInventory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pokedex.json")
+	cfg := newCanalaveConfig(t, 1)
+	cfg.Collection[1] = pokeapi.UserPokemon{PokemonData: pokeapi.PokemonData{Name: "pikachu"}, UID: 1, Level: 5}
+	cfg.Party = []int{1}
+	if err := commandGive(cfg, "everstone", "pikachu"); err != nil {
+		t.Fatalf("give returned error: %v", err)
+	}
+	cfg.Inventory["pokeball"] = 0
+
+	if err := savePokedex(path, cfg); err != nil {
+		t.Fatalf("savePokedex returned error: %v", err)
+	}
+	saveData, err := loadPokedex(path)
+	if err != nil {
+		t.Fatalf("loadPokedex returned error: %v", err)
+	}
+	restored := newCanalaveConfig(t, 1)
+	saveData.apply(restored)
+	if restored.Inventory["everstone"] != 0 || restored.Collection[1].HeldItem != "everstone" {
+		t.Errorf("restored everstones = %d, pikachu holds %q; want the one everstone held by pikachu", restored.Inventory["everstone"], restored.Collection[1].HeldItem)
+	}
+	if restored.Inventory["pokeball"] != 0 || restored.Inventory["greatball"] != cfg.Inventory["greatball"] {
+		t.Errorf("restored inventory = %v; want %v", restored.Inventory, cfg.Inventory)
+	}
+
+	// Saves from before the inventory was saved start with the items of a new game.
+	if err := os.WriteFile(path, []byte(`{"version": 5, "party_uids": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	saveData, _ = loadPokedex(path)
+	restored = newCanalaveConfig(t, 1)
+	saveData.apply(restored)
+	if restored.Inventory["everstone"] != 1 || restored.Inventory["pokeball"] != 10 {
+		t.Errorf("inventory of a version 5 save = %v; want the new game items", restored.Inventory)
+	}
+}
+
+func TestLoadMigratesOldSaves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pokedex.json")
+	// A save from before Pokemon had UIDs and happiness.
+	old := `{"pokedex": {"pikachu": {"name": "pikachu", "level": 5}, "eevee": {"name": "eevee", "level": 3}}, "party": [{"name": "pikachu", "level": 5}]}`
+	if err := os.WriteFile(path, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+	saveData, err := loadPokedex(path)
+	if err != nil {
+		t.Fatalf("loadPokedex returned error: %v", err)
+	}
+
+	cfg := newCanalaveConfig(t, 1)
+	saveData.apply(cfg)
+	eevee, pikachu := cfg.Collection[1], cfg.Collection[2]
+	if eevee.Name != "eevee" || pikachu.Name != "pikachu" {
+		t.Errorf("collection = %v; want eevee #1 and pikachu #2, in name order", cfg.Collection)
+	}
+	if cfg.Party[0] != 2 {
+		t.Errorf("party pikachu UID = %d; want its Pokedex entry's 2", cfg.Party[0])
+	}
+	if eevee.Happiness != pokeapi.DefaultHappiness || cfg.Collection[cfg.Party[0]].Happiness != pokeapi.DefaultHappiness {
+		t.Errorf("Pokemon from old saves should start at the default happiness, got %d and %d", eevee.Happiness, cfg.Collection[cfg.Party[0]].Happiness)
+	}
+	if !cfg.Dex.Caught["eevee"] || !cfg.Dex.Caught["pikachu"] || len(cfg.Dex.Seen) != 2 {
+		t.Errorf("Pokedex = %+v; want the owned eevee and pikachu registered as caught", cfg.Dex)
+	}
+	if !slices.Equal(cfg.Boxes[0].Pokemon, []int{1}) {
+		t.Errorf("Box 1 = %v; want the eevee outside the party, #1", cfg.Boxes[0].Pokemon)
+	}
+	if uid := cfg.newUID(); uid != 3 {
+		t.Errorf("next UID = %d; want 3", uid)
+	}
+
+	if err := savePokedex(path, cfg); err != nil {
+		t.Fatalf("savePokedex returned error: %v", err)
+	}
+	saveData, _ = loadPokedex(path)
+	restored := newCanalaveConfig(t, 1)
+	saveData.apply(restored)
+	restoredPikachu := restored.Collection[2]
+	restoredPikachu.ChangeHappiness(pokeapi.HappinessFaint)
+	storeOwnedPokemon(restored, restoredPikachu)
+	if err := savePokedex(path, restored); err != nil {
+		t.Fatalf("savePokedex returned error: %v", err)
+	}
+	saveData, _ = loadPokedex(path)
+	saveData.apply(restored)
+	if got := restored.Collection[2]; got.Name != "pikachu" || got.Happiness != pokeapi.DefaultHappiness-1 {
+		t.Errorf("after a round trip #2 = %s with happiness %d; want pikachu with %d", got.Name, got.Happiness, pokeapi.DefaultHappiness-1)
+	}
+	if restored.NextUID != 4 {
+		t.Errorf("after a round trip the next UID = %d; want 4", restored.NextUID)
+	}
+}
+
+func TestLoadMigratesPartyCopiesToUIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pokedex.json")
+	// A version 4 save, with the party kept as copies of its Pokemon. Its #3 is missing
+	// from the Pokemon list.
+	old := `{"version": 4, "pokemon": [{"uid": 1, "name": "pikachu", "level": 5}, {"uid": 2, "name": "eevee", "level": 3}],
+		"party": [{"uid": 2, "name": "eevee", "level": 3}, {"uid": 3, "name": "mew", "level": 9}], "next_uid": 4}`
+	if err := os.WriteFile(path, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+	saveData, err := loadPokedex(path)
+	if err != nil {
+		t.Fatalf("loadPokedex returned error: %v", err)
+	}
+
+	cfg := newCanalaveConfig(t, 1)
+	saveData.apply(cfg)
+	if !slices.Equal(cfg.Party, []int{2, 4}) || cfg.Collection[4].Name != "mew" {
+		t.Errorf("party = %v, #4 = %q; want eevee #2 and the missing mew added as #4", cfg.Party, cfg.Collection[4].Name)
+	}
+	if !slices.Equal(cfg.Boxes[0].Pokemon, []int{1}) {
+		t.Errorf("Box 1 = %v; want the pikachu outside the party, #1", cfg.Boxes[0].Pokemon)
+	}
+
+	if err := savePokedex(path, cfg); err != nil {
+		t.Fatalf("savePokedex returned error: %v", err)
+	}
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(saved), `"name": "eevee"`); n != 1 {
+		t.Errorf("the save holds eevee %d times; want once:\n%s", n, saved)
+	}
+	saveData, _ = loadPokedex(path)
+	restored := newCanalaveConfig(t, 1)
+	saveData.apply(restored)
+	if !slices.Equal(restored.Party, []int{2, 4}) {
+		t.Errorf("after a round trip the party = %v; want [2 4]", restored.Party)
+	}
+}
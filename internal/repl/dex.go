@@ -0,0 +1,41 @@
+package repl
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/voidarchive/pokedex/internal/shared/constants"
+)
+
+// Dex is the player's Pokedex: the species they have seen, in the wild or in battle,
+// and the species they have caught. A caught species counts as seen. Species are kept
+// by name, so the Pokedex can be ordered by any numbering PokeAPI has.
+type Dex struct {
+	Seen   map[string]bool
+	Caught map[string]bool
+}
+
+// newDex returns an empty Pokedex.
+func newDex() Dex {
+	return Dex{Seen: make(map[string]bool), Caught: make(map[string]bool)}
+}
+
+// markSeen registers a species the player has come across.
+func (d Dex) markSeen(species string) {
+	d.Seen[species] = true
+}
+
+// markCaught registers a species the player has owned, by catching or evolving it.
+// Like in the games, the first time announces the new Pokedex entry.
+func (d Dex) markCaught(species string) {
+	if !d.Caught[species] {
+		fmt.Printf("%s%s%s's data was added to the Pokedex.%s\n", constants.ColorYellow, species, constants.ColorGray, constants.ColorReset)
+	}
+	d.Seen[species] = true
+	d.Caught[species] = true
+}
+
+// seenSpecies and caughtSpecies list the registered species by name, for saving.
+func (d Dex) seenSpecies() []string   { return slices.Sorted(maps.Keys(d.Seen)) }
+func (d Dex) caughtSpecies() []string { return slices.Sorted(maps.Keys(d.Caught)) }
@@ -0,0 +1,155 @@
+package repl
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+
+	"github.com/voidarchive/pokedex/internal/battle"
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/shared/constants"
+)
+
+// defaultWildLevel is used for wild Pokemon whose encounter has no level data.
+const defaultWildLevel = 5
+
+// Encounter is the wild Pokemon the player is facing, started by 'explore'. It lasts
+// until the wild Pokemon is caught or faints, the player runs, or explores elsewhere.
+type Encounter struct {
+	Pokemon pokeapi.PokemonData
+	Level   int
+	Area    string
+	Wild    battle.Combatant // The wild Pokemon's HP carries over between battle rounds
+	Turn    int              // Battle rounds fought and balls thrown so far
+	// FighterHP is the HP left of each of the player's Pokemon that fought in this
+	// encounter, by UID, and FighterStatus the status they were left with.
+	FighterHP     map[int]int
+	FighterStatus map[int]battle.Status
+}
+
+// newEncounter starts an encounter with a wild Pokemon at full HP.
+func newEncounter(pokemon pokeapi.PokemonData, level int, area string) *Encounter {
+	return &Encounter{
+		Pokemon:       pokemon,
+		Level:         level,
+		Area:          area,
+		Wild:          battle.NewCombatant(pokemon, constants.ColorRed),
+		FighterHP:     make(map[int]int),
+		FighterStatus: make(map[int]battle.Status),
+	}
+}
+
+// defaultMethod is how the player looks for wild Pokemon unless 'explore --method'
+// says otherwise.
+const defaultMethod = "walk"
+
+// defaultConditions are the encounter conditions that hold in the normal state of the
+// world: no swarm, Poke Radar off, no game in the GBA slot and the radio off.
+var defaultConditions = map[string]bool{
+	"swarm-no":   true,
+	"radar-off":  true,
+	"slot2-none": true,
+	"radio-off":  true,
+}
+
+// encounterAvailable returns a filter for encounter details whose conditions hold right
+// now. Time of day and season conditions follow cfg's clock.
+func encounterAvailable(cfg *Config) func(pokeapi.Encounter) bool {
+	now := cfg.now()
+	return func(e pokeapi.Encounter) bool {
+		for _, condition := range e.ConditionValues {
+			switch {
+			case strings.HasPrefix(condition.Name, "time-"):
+				if condition.Name != "time-"+timeOfDay(now) {
+					return false
+				}
+			case strings.HasPrefix(condition.Name, "season-"):
+				if condition.Name != "season-"+season(now) {
+					return false
+				}
+			case !defaultConditions[condition.Name]:
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// pickEncounterSlot picks a slot of the encounter table, weighted by its chance.
+func pickEncounterSlot(r *rand.Rand, table []pokeapi.EncounterSlot) pokeapi.EncounterSlot {
+	total := 0
+	for _, slot := range table {
+		total += slot.Chance
+	}
+	roll := r.Intn(total)
+	for _, slot := range table {
+		if roll < slot.Chance {
+			return slot
+		}
+		roll -= slot.Chance
+	}
+	return table[len(table)-1]
+}
+
+// slotLevel picks the level of a wild Pokemon inside the slot's level range.
+func slotLevel(r *rand.Rand, slot pokeapi.EncounterSlot) int {
+	if slot.MaxLevel < 1 {
+		return defaultWildLevel
+	}
+	minLevel := min(max(slot.MinLevel, 1), slot.MaxLevel)
+	return minLevel + r.Intn(slot.MaxLevel-minLevel+1)
+}
+
+// otherMethods lists the encounter methods of the area other than method.
+func otherMethods(area pokeapi.LocationAreaDetail, method string) []string {
+	var methods []string
+	for _, encounter := range area.PokemonEncounters {
+		for _, m := range encounter.Methods() {
+			if m != method && !slices.Contains(methods, m) {
+				methods = append(methods, m)
+			}
+		}
+	}
+	return methods
+}
+
+// parseMethodFlag extracts "--method <name>" or "--method=<name>" from args and
+// returns the remaining arguments with the method, defaulting to walking.
+func parseMethodFlag(args []string) ([]string, string, error) {
+	method := defaultMethod
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--method":
+			if i+1 >= len(args) {
+				return nil, "", fmt.Errorf("%s--method needs a value, e.g. '--method surf'%s", constants.ColorYellow, constants.ColorReset)
+			}
+			method = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "--method="):
+			method = strings.TrimPrefix(args[i], "--method=")
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	return rest, strings.ToLower(method), nil
+}
+
+// printWildHP shows how much HP the wild Pokemon has left.
+func printWildHP(e *Encounter) {
+	fmt.Printf("  %sWild %s%s%s HP: %s%d%s/%d%s\n", constants.ColorRed, constants.ColorYellow, e.Pokemon.Name, constants.ColorRed, constants.ColorBrightRed, max(e.Wild.HP, 0), constants.ColorReset, e.Wild.MaxHP, statusTag(e.Wild.Status))
+}
+
+// statusTag marks a status after an HP readout, e.g. " [burn]".
+func statusTag(status battle.Status) string {
+	if status == battle.StatusNone {
+		return ""
+	}
+	return fmt.Sprintf(" %s[%s]%s", constants.ColorPurple, status, constants.ColorReset)
+}
+
+// errNoEncounter is returned by encounter commands when no wild Pokemon is around.
+func errNoEncounter(action string) error {
+	return fmt.Errorf("%sthere's no wild Pokemon to %s. Use 'explore <area>' to find one%s", constants.ColorYellow, action, constants.ColorReset)
+}
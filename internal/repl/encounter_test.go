@@ -0,0 +1,100 @@
+package repl
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+)
+
+func TestPickEncounterSlotIsWeightedByChance(t *testing.T) {
+	table := []pokeapi.EncounterSlot{
+		{Pokemon: "tentacool", Chance: 60, MinLevel: 20, MaxLevel: 30},
+		{Pokemon: "tentacruel", Chance: 5, MinLevel: 20, MaxLevel: 40},
+		{Pokemon: "wingull", Chance: 35, MinLevel: 20, MaxLevel: 30},
+	}
+	r := rand.New(rand.NewSource(1))
+
+	counts := map[string]int{}
+	for range 10000 {
+		slot := pickEncounterSlot(r, table)
+		counts[slot.Pokemon]++
+		if level := slotLevel(r, slot); level < slot.MinLevel || level > slot.MaxLevel {
+			t.Fatalf("level %d outside %s's range %d-%d", level, slot.Pokemon, slot.MinLevel, slot.MaxLevel)
+		}
+	}
+
+	for _, slot := range table {
+		share := float64(counts[slot.Pokemon]) / 10000 * 100
+		if share < float64(slot.Chance)-3 || share > float64(slot.Chance)+3 {
+			t.Errorf("%s picked %.1f%% of the time; want about %d%%", slot.Pokemon, share, slot.Chance)
+		}
+	}
+}
+
+func TestSlotLevelWithoutLevelData(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	if level := slotLevel(r, pokeapi.EncounterSlot{Pokemon: "missingno"}); level != defaultWildLevel {
+		t.Errorf("slotLevel without a range = %d; want %d", level, defaultWildLevel)
+	}
+	if level := slotLevel(r, pokeapi.EncounterSlot{MinLevel: 0, MaxLevel: 1}); level != 1 {
+		t.Errorf("slotLevel(0-1) = %d; want 1", level)
+	}
+}
+
+func TestEncounterAvailable(t *testing.T) {
+	night := time.Date(2024, time.July, 1, 22, 0, 0, 0, time.UTC)
+	cfg := &Config{Clock: NewSimulatedClock(night)}
+	available := encounterAvailable(cfg)
+
+	tests := []struct {
+		conditions []string
+		want       bool
+	}{
+		{nil, true},
+		{[]string{"time-night"}, true},
+		{[]string{"time-morning"}, false},
+		{[]string{"swarm-no", "radar-off", "slot2-none"}, true},
+		{[]string{"swarm-yes"}, false},
+		{[]string{"radar-on"}, false},
+		{[]string{"season-summer"}, true},
+		{[]string{"season-winter"}, false},
+		{[]string{"story-progress-beat-elite-four"}, false},
+	}
+	for _, tc := range tests {
+		var e pokeapi.Encounter
+		for _, c := range tc.conditions {
+			e.ConditionValues = append(e.ConditionValues, pokeapi.NamedAPIResource{Name: c})
+		}
+		if got := available(e); got != tc.want {
+			t.Errorf("available(%v) at night in July = %v; want %v", tc.conditions, got, tc.want)
+		}
+	}
+}
+
+func TestParseMethodFlag(t *testing.T) {
+	tests := []struct {
+		args       []string
+		wantRest   []string
+		wantMethod string
+	}{
+		{[]string{"route-1-area"}, []string{"route-1-area"}, "walk"},
+		{[]string{"route-1-area", "--method", "surf"}, []string{"route-1-area"}, "surf"},
+		{[]string{"--method=Old-Rod", "3"}, []string{"3"}, "old-rod"},
+	}
+	for _, tc := range tests {
+		rest, method, err := parseMethodFlag(tc.args)
+		if err != nil {
+			t.Errorf("parseMethodFlag(%v) returned error: %v", tc.args, err)
+			continue
+		}
+		if method != tc.wantMethod || len(rest) != len(tc.wantRest) || (len(rest) > 0 && rest[0] != tc.wantRest[0]) {
+			t.Errorf("parseMethodFlag(%v) = %v, %s; want %v, %s", tc.args, rest, method, tc.wantRest, tc.wantMethod)
+		}
+	}
+
+	if _, _, err := parseMethodFlag([]string{"route-1-area", "--method"}); err == nil {
+		t.Errorf("expected an error for --method without a value")
+	}
+}
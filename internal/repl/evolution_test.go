@@ -0,0 +1,353 @@
+package repl
+
+import (
+	"io"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/pokeapi/pokeapitest"
+)
+
+func newEvolutionFake() *pokeapitest.Fake {
+	return pokeapitest.NewFake().
+		AddPokemon(
+			pokeapitest.NewPokemon(4, "charmander", 62, pokeapitest.Stats{HP: 39, Attack: 52, Defense: 43, SpecialAttack: 60, SpecialDefense: 50, Speed: 65}, "fire"),
+			pokeapitest.NewPokemon(5, "charmeleon", 142, pokeapitest.Stats{HP: 58, Attack: 64, Defense: 58, SpecialAttack: 80, SpecialDefense: 65, Speed: 80}, "fire"),
+		).
+		AddEvolutionChain(pokeapitest.ChainURL(2), pokeapi.EvolutionChainResponse{
+			ID: 2,
+			Chain: pokeapitest.Link("charmander", nil,
+				pokeapitest.Link("charmeleon", pokeapitest.LevelUp(16),
+					pokeapitest.Link("charizard", pokeapitest.LevelUp(36)),
+				),
+			),
+		})
+}
+
+func ownPokemon(t *testing.T, cfg *Config, name string, level int) pokeapi.UserPokemon {
+	t.Helper()
+	data, err := cfg.PokeapiClient.FetchPokemon(name)
+	if err != nil {
+		t.Fatalf("FetchPokemon(%s): %v", name, err)
+	}
+	p := pokeapi.NewUserPokemon(data, level)
+	p.CaughtTimestamp = time.Now().UnixNano()
+	p, _ = addOwnedPokemon(cfg, p)
+	return p
+}
+
+func TestCheckAndHandleEvolution(t *testing.T) {
+	tests := []struct {
+		name        string
+		level       int
+		wantEvolved bool
+		wantSpecies string
+	}{
+		{name: "below min level", level: 15, wantEvolved: false, wantSpecies: "charmander"},
+		{name: "at min level", level: 16, wantEvolved: true, wantSpecies: "charmeleon"},
+		{name: "above min level", level: 20, wantEvolved: true, wantSpecies: "charmeleon"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewConfig(newEvolutionFake(), nil, rand.New(rand.NewSource(1)))
+			charmander := ownPokemon(t, cfg, "charmander", tc.level)
+			charmander.CurrentXP = 42
+			storeOwnedPokemon(cfg, charmander)
+
+			evolved, err := CheckAndHandleEvolution(cfg, charmander.UID)
+			if err != nil {
+				t.Fatalf("CheckAndHandleEvolution returned error: %v", err)
+			}
+			if evolved != tc.wantEvolved {
+				t.Errorf("evolved = %v; want %v", evolved, tc.wantEvolved)
+			}
+			p := cfg.Collection[charmander.UID]
+			if p.Name != tc.wantSpecies {
+				t.Fatalf("#%d is a %s; want %s", charmander.UID, p.Name, tc.wantSpecies)
+			}
+			if p.Level != tc.level {
+				t.Errorf("level = %d; want %d", p.Level, tc.level)
+			}
+			if partyPokemon(cfg)[0].Name != tc.wantSpecies {
+				t.Errorf("party slot 1 = %s; want %s", partyPokemon(cfg)[0].Name, tc.wantSpecies)
+			}
+			if p.UID != charmander.UID || p.CurrentXP != 42 || p.CaughtTimestamp != charmander.CaughtTimestamp {
+				t.Errorf("the Pokemon must keep its UID, XP and catch date, got UID %d, XP %d, caught %d", p.UID, p.CurrentXP, p.CaughtTimestamp)
+			}
+			if tc.wantEvolved && (len(p.Evolutions) != 1 || p.Evolutions[0].From != "charmander" || p.Evolutions[0].Level != tc.level) {
+				t.Errorf("evolution history = %+v; want charmander -> charmeleon at Lv %d", p.Evolutions, tc.level)
+			}
+		})
+	}
+}
+
+func TestCheckAndHandleEvolutionErrors(t *testing.T) {
+	cfg := NewConfig(newEvolutionFake(), nil, rand.New(rand.NewSource(1)))
+
+	if _, err := CheckAndHandleEvolution(cfg, 1); err == nil {
+		t.Errorf("expected an error for a Pokemon that hasn't been caught")
+	}
+
+	// charizard's data was never added to the fake, so evolving into it must fail cleanly.
+	p := ownPokemon(t, cfg, "charmeleon", 36)
+	evolved, err := CheckAndHandleEvolution(cfg, p.UID)
+	if err == nil || evolved {
+		t.Fatalf("expected evolving into unknown data to fail, got evolved=%v err=%v", evolved, err)
+	}
+	if got := cfg.Collection[p.UID]; got.Name != "charmeleon" || got.Level != p.Level {
+		t.Errorf("a failed evolution must leave the Pokemon untouched, got %+v", got)
+	}
+}
+
+func newEeveeFake() *pokeapitest.Fake {
+	stats := pokeapitest.Stats{HP: 65, Attack: 65, Defense: 60, SpecialAttack: 110, SpecialDefense: 95, Speed: 110}
+	return pokeapitest.NewFake().
+		AddPokemon(
+			pokeapitest.NewPokemon(133, "eevee", 65, pokeapitest.Stats{HP: 55, Attack: 55, Defense: 50, SpecialAttack: 45, SpecialDefense: 65, Speed: 55}, "normal"),
+			pokeapitest.NewPokemon(134, "vaporeon", 184, stats, "water"),
+			pokeapitest.NewPokemon(196, "espeon", 184, stats, "psychic"),
+			pokeapitest.NewPokemon(197, "umbreon", 184, stats, "dark"),
+		).
+		AddEvolutionChain(pokeapitest.ChainURL(67), pokeapi.EvolutionChainResponse{
+			ID: 67,
+			Chain: pokeapitest.Link("eevee", nil,
+				pokeapitest.Link("vaporeon", pokeapitest.UseItem("water-stone")),
+				pokeapitest.Link("espeon", pokeapitest.LevelUpAt("day")),
+				pokeapitest.Link("umbreon", pokeapitest.LevelUpAt("night")),
+			),
+		})
+}
+
+func TestEvolutionFollowsTheTimeOfDay(t *testing.T) {
+	tests := []struct {
+		hour        int
+		wantSpecies string
+	}{
+		{hour: 6, wantSpecies: "espeon"}, // morning counts as day
+		{hour: 14, wantSpecies: "espeon"},
+		{hour: 22, wantSpecies: "umbreon"},
+	}
+
+	for _, tc := range tests {
+		cfg := NewConfig(newEeveeFake(), nil, rand.New(rand.NewSource(1)))
+		cfg.Clock = NewSimulatedClock(time.Date(2024, time.May, 1, tc.hour, 0, 0, 0, time.UTC))
+		eevee := ownPokemon(t, cfg, "eevee", 10)
+
+		evolved, err := CheckAndHandleEvolution(cfg, eevee.UID)
+		if err != nil || !evolved {
+			t.Fatalf("%02d:00: expected eevee to evolve, got evolved=%v err=%v", tc.hour, evolved, err)
+		}
+		if got := cfg.Collection[eevee.UID].Name; got != tc.wantSpecies {
+			t.Errorf("%02d:00: eevee evolved into %s; want %s", tc.hour, got, tc.wantSpecies)
+		}
+	}
+}
+
+func TestEvolutionContextFromTheGame(t *testing.T) {
+	stats := pokeapitest.Stats{HP: 50, Attack: 50, Defense: 50, SpecialAttack: 50, SpecialDefense: 50, Speed: 50}
+	fairyMove := pokeapitest.LevelUp(1)
+	fairyMove[0].MinLevel = nil
+	fairyMove[0].KnownMoveType = &pokeapi.NamedAPIResource{Name: "fairy"}
+	withRemoraid := pokeapitest.LevelUp(1)
+	withRemoraid[0].MinLevel = nil
+	withRemoraid[0].PartySpecies = &pokeapi.NamedAPIResource{Name: "remoraid"}
+
+	fake := pokeapitest.NewFake().
+		AddPokemon(
+			pokeapitest.LearnsAt(pokeapitest.NewPokemon(133, "eevee", 65, stats, "normal"), 15, "baby-doll-eyes"),
+			pokeapitest.NewPokemon(700, "sylveon", 184, stats, "fairy"),
+			pokeapitest.NewPokemon(458, "mantyke", 69, stats, "water", "flying"),
+			pokeapitest.NewPokemon(226, "mantine", 170, stats, "water", "flying"),
+			pokeapitest.NewPokemon(223, "remoraid", 60, stats, "water"),
+		).
+		AddMove(pokeapitest.Move("baby-doll-eyes", "fairy")).
+		AddEvolutionChain(pokeapitest.ChainURL(67), pokeapi.EvolutionChainResponse{
+			ID:    67,
+			Chain: pokeapitest.Link("eevee", nil, pokeapitest.Link("sylveon", fairyMove)),
+		}).
+		AddEvolutionChain(pokeapitest.ChainURL(116), pokeapi.EvolutionChainResponse{
+			ID:    116,
+			Chain: pokeapitest.Link("mantyke", nil, pokeapitest.Link("mantine", withRemoraid)),
+		})
+
+	cfg := NewConfig(fake, nil, rand.New(rand.NewSource(1)))
+	eevee := ownPokemon(t, cfg, "eevee", 14)
+	if evolved, err := CheckAndHandleEvolution(cfg, eevee.UID); err != nil || evolved {
+		t.Fatalf("eevee hasn't learned a fairy move at level 14, got evolved=%v err=%v", evolved, err)
+	}
+	eevee.Level = 15
+	storeOwnedPokemon(cfg, eevee)
+	if evolved, err := CheckAndHandleEvolution(cfg, eevee.UID); err != nil || !evolved {
+		t.Fatalf("eevee knowing baby-doll-eyes should evolve into sylveon, got evolved=%v err=%v", evolved, err)
+	}
+
+	mantyke := ownPokemon(t, cfg, "mantyke", 10)
+	if evolved, _ := CheckAndHandleEvolution(cfg, mantyke.UID); evolved {
+		t.Fatalf("mantyke should need a remoraid in the party")
+	}
+	ownPokemon(t, cfg, "remoraid", 10)
+	if evolved, err := CheckAndHandleEvolution(cfg, mantyke.UID); err != nil || !evolved {
+		t.Fatalf("mantyke with a remoraid in the party should evolve, got evolved=%v err=%v", evolved, err)
+	}
+	if got := cfg.Collection[mantyke.UID].Name; got != "mantine" {
+		t.Errorf("mantyke evolved into %s; want mantine", got)
+	}
+}
+
+// answers is a LineReader that replies to prompts with canned answers.
+type answers []string
+
+func (a *answers) ReadLine(prompt string) (string, error) {
+	if len(*a) == 0 {
+		return "", io.EOF
+	}
+	answer := (*a)[0]
+	*a = (*a)[1:]
+	return answer, nil
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name       string
+		answers    answers
+		defaultYes bool
+		want       bool
+	}{
+		{name: "yes", answers: answers{"y"}, want: true},
+		{name: "no", answers: answers{"No"}, defaultYes: true, want: false},
+		{name: "b cancels", answers: answers{"b"}, defaultYes: true, want: false},
+		{name: "empty takes the default", answers: answers{""}, defaultYes: true, want: true},
+		{name: "out of input takes the default", answers: answers{}, defaultYes: false, want: false},
+		{name: "asks again", answers: answers{"maybe", "yes"}, want: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewConfig(pokeapitest.NewFake(), nil, rand.New(rand.NewSource(1)))
+			cfg.Input = &tc.answers
+			if got := confirm(cfg, "Continue?", tc.defaultYes); got != tc.want {
+				t.Errorf("confirm = %v; want %v", got, tc.want)
+			}
+			if len(tc.answers) != 0 {
+				t.Errorf("unread answers left: %v", tc.answers)
+			}
+		})
+	}
+}
+
+func TestPlayerCanStopAnEvolution(t *testing.T) {
+	cfg := NewConfig(newEvolutionFake(), nil, rand.New(rand.NewSource(1)))
+	cfg.Input = &answers{"n"}
+	charmander := ownPokemon(t, cfg, "charmander", 16)
+
+	evolved, err := CheckAndHandleEvolution(cfg, charmander.UID)
+	if err != nil || evolved {
+		t.Fatalf("answering no should stop the evolution, got evolved=%v err=%v", evolved, err)
+	}
+	if cfg.Collection[charmander.UID].Name != "charmander" || partyPokemon(cfg)[0].Name != "charmander" {
+		t.Errorf("charmander should stay a charmander, collection = %v", cfg.Collection)
+	}
+
+	// It asks again at the next level.
+	cfg.Input = &answers{"y"}
+	if evolved, err := CheckAndHandleEvolution(cfg, charmander.UID); err != nil || !evolved {
+		t.Fatalf("expected charmander to evolve the next time, got evolved=%v err=%v", evolved, err)
+	}
+}
+
+func TestEverstoneStopsEvolution(t *testing.T) {
+	cfg := NewConfig(newEeveeFake(), nil, rand.New(rand.NewSource(1)))
+	p := ownPokemon(t, cfg, "eevee", 10)
+	p.HeldItem = everstone
+	storeOwnedPokemon(cfg, p)
+
+	if evolved, err := CheckAndHandleEvolution(cfg, p.UID); err != nil || evolved {
+		t.Fatalf("an Everstone should stop eevee from evolving, got evolved=%v err=%v", evolved, err)
+	}
+
+	// An evolution stone still works, and the Everstone stays with the Pokemon.
+	if err := commandUse(cfg, "water-stone", "eevee"); err != nil {
+		t.Fatalf("use water-stone: %v", err)
+	}
+	vaporeon := cfg.Collection[p.UID]
+	if vaporeon.Name != "vaporeon" {
+		t.Fatalf("expected eevee to evolve into vaporeon, got %s", vaporeon.Name)
+	}
+	if vaporeon.HeldItem != everstone {
+		t.Errorf("held item = %q; want the everstone", vaporeon.HeldItem)
+	}
+}
+
+func TestUseEvolutionStone(t *testing.T) {
+	cfg := NewConfig(newEeveeFake(), nil, rand.New(rand.NewSource(1)))
+	eevee := ownPokemon(t, cfg, "eevee", 5)
+
+	if err := commandUse(cfg, "fire-stone", "eevee"); err == nil {
+		t.Errorf("a fire-stone should have no effect on this eevee")
+	}
+	if cfg.Inventory["fire-stone"] != 1 {
+		t.Errorf("an item without effect must not be used up, have %d", cfg.Inventory["fire-stone"])
+	}
+	if err := commandUse(cfg, "dawn-stone", "eevee"); err == nil {
+		t.Errorf("expected an error for an item the player doesn't have")
+	}
+	if err := commandUse(cfg, "water-stone", "pikachu"); err == nil {
+		t.Errorf("expected an error for a Pokemon that hasn't been caught")
+	}
+
+	if err := commandUse(cfg, "water-stone", "eevee"); err != nil {
+		t.Fatalf("use water-stone: %v", err)
+	}
+	if cfg.Inventory["water-stone"] != 0 {
+		t.Errorf("the water-stone should be used up, have %d", cfg.Inventory["water-stone"])
+	}
+	if cfg.Collection[eevee.UID].Name != "vaporeon" || partyPokemon(cfg)[0].Name != "vaporeon" {
+		t.Errorf("expected eevee to evolve into vaporeon, collection = %v, party = %v", cfg.Collection, cfg.Party)
+	}
+}
+
+func TestHappinessEvolution(t *testing.T) {
+	stats := pokeapitest.Stats{HP: 35, Attack: 55, Defense: 40, SpecialAttack: 50, SpecialDefense: 50, Speed: 90}
+	friendship := pokeapitest.LevelUp(1)
+	friendship[0].MinLevel = nil
+	friendship[0].MinHappiness = intPtr(160)
+	fake := pokeapitest.NewFake().
+		AddPokemon(
+			pokeapitest.NewPokemon(172, "pichu", 41, stats, "electric"),
+			pokeapitest.NewPokemon(25, "pikachu", 112, stats, "electric"),
+		).
+		AddEvolutionChain(pokeapitest.ChainURL(10), pokeapi.EvolutionChainResponse{
+			ID:    10,
+			Chain: pokeapitest.Link("pichu", nil, pokeapitest.Link("pikachu", friendship)),
+		})
+
+	cfg := NewConfig(fake, nil, rand.New(rand.NewSource(1)))
+	pichu := ownPokemon(t, cfg, "pichu", 10)
+	pichu.Happiness = 150
+	storeOwnedPokemon(cfg, pichu)
+	if evolved, err := CheckAndHandleEvolution(cfg, pichu.UID); err != nil || evolved {
+		t.Fatalf("pichu isn't happy enough to evolve, got evolved=%v err=%v", evolved, err)
+	}
+
+	cfg.Inventory["pomeg-berry"] = 2
+	if err := commandUse(cfg, "pomeg-berry", "pichu"); err != nil {
+		t.Fatalf("use pomeg-berry: %v", err)
+	}
+	if got := cfg.Collection[pichu.UID].Happiness; got != 155 || partyPokemon(cfg)[0].Happiness != 155 {
+		t.Fatalf("happiness after a berry = %d (party %d); want 155", got, partyPokemon(cfg)[0].Happiness)
+	}
+	if err := commandUse(cfg, "pomeg-berry", "pichu"); err != nil {
+		t.Fatalf("use pomeg-berry: %v", err)
+	}
+	if evolved, err := CheckAndHandleEvolution(cfg, pichu.UID); err != nil || !evolved {
+		t.Fatalf("a happy pichu should evolve, got evolved=%v err=%v", evolved, err)
+	}
+	if got := cfg.Collection[pichu.UID]; got.Name != "pikachu" || got.Happiness != 160 {
+		t.Errorf("#%d = %s with happiness %d; want a pikachu keeping pichu's 160", pichu.UID, got.Name, got.Happiness)
+	}
+}
+
+func intPtr(i int) *int { return &i }
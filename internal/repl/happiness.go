@@ -0,0 +1,72 @@
+package repl
+
+import (
+	"fmt"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/shared/constants"
+)
+
+// happinessItems are the items 'use' feeds a Pokemon to make it happier.
+var happinessItems = map[string]pokeapi.HappinessChange{
+	"pomeg-berry":  pokeapi.HappinessBerry,
+	"kelpsy-berry": pokeapi.HappinessBerry,
+	"qualot-berry": pokeapi.HappinessBerry,
+	"hondew-berry": pokeapi.HappinessBerry,
+	"grepa-berry":  pokeapi.HappinessBerry,
+	"tamato-berry": pokeapi.HappinessBerry,
+}
+
+// changeHappiness applies change to an owned Pokemon and stores it, returning by how
+// much its happiness actually changed.
+func changeHappiness(cfg *Config, uid int, change pokeapi.HappinessChange) int {
+	pokemon, owned := cfg.Collection[uid]
+	if !owned {
+		return 0
+	}
+	delta := pokemon.ChangeHappiness(change)
+	storeOwnedPokemon(cfg, pokemon)
+	return delta
+}
+
+// walkParty makes every party member a little happier for each location walked to.
+func walkParty(cfg *Config, steps int) {
+	for _, uid := range cfg.Party {
+		for range steps {
+			changeHappiness(cfg, uid, pokeapi.HappinessWalk)
+		}
+	}
+}
+
+// useHappinessItem feeds item to a Pokemon. Like in the games, a Pokemon that is as
+// happy as it can be won't take it, and the item is kept.
+func useHappinessItem(cfg *Config, item string, pokemon pokeapi.UserPokemon) error {
+	if pokemon.Happiness >= pokeapi.MaxHappiness {
+		return fmt.Errorf("%sthe %s%s%s won't have any effect on %s: it couldn't be any happier%s", constants.ColorYellow, constants.ColorBrightRed, item, constants.ColorYellow, pokemon.DisplayName(), constants.ColorReset)
+	}
+	cfg.Inventory[item]--
+	changeHappiness(cfg, pokemon.UID, happinessItems[item])
+	fmt.Printf("%sYou used the %s%s%s on %s%s%s. %s%s\n", constants.ColorCyan, constants.ColorYellow, item, constants.ColorCyan, constants.ColorYellow, pokemon.DisplayName(), constants.ColorCyan, happinessText(cfg.Collection[pokemon.UID].Happiness), constants.ColorReset)
+	return nil
+}
+
+// happinessText describes how a Pokemon feels about the player, like the games'
+// friendship checkers do.
+func happinessText(happiness int) string {
+	switch {
+	case happiness >= pokeapi.MaxHappiness:
+		return "It adores you!"
+	case happiness >= 200:
+		return "It's very friendly toward you."
+	case happiness >= 150:
+		return "It's quite friendly toward you."
+	case happiness >= 100:
+		return "It's warming up to you."
+	case happiness >= 50:
+		return "It's not used to you yet."
+	case happiness > 0:
+		return "It doesn't seem to like you much."
+	default:
+		return "It really dislikes you."
+	}
+}
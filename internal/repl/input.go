@@ -0,0 +1,49 @@
+package repl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chzyer/readline"
+	"github.com/voidarchive/pokedex/internal/shared/constants"
+)
+
+// readlineInput reads prompt answers from the REPL's readline instance.
+type readlineInput struct {
+	rl *readline.Instance
+}
+
+func (in readlineInput) ReadLine(prompt string) (string, error) {
+	in.rl.SetPrompt(prompt)
+	return in.rl.Readline()
+}
+
+// confirm asks the player a yes/no question. An empty answer takes the default, and so
+// does running out of input; anything other than yes or no asks again. "b" is a no,
+// like pressing B in the games.
+func confirm(cfg *Config, question string, defaultYes bool) bool {
+	if cfg.Input == nil {
+		return defaultYes
+	}
+	choices := "y/N"
+	if defaultYes {
+		choices = "Y/n"
+	}
+	prompt := fmt.Sprintf("%s%s (%s) > %s", constants.ColorBrightYellow, question, choices, constants.ColorReset)
+
+	for {
+		answer, err := cfg.Input.ReadLine(prompt)
+		if err != nil {
+			return defaultYes
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "":
+			return defaultYes
+		case "y", "yes":
+			return true
+		case "n", "no", "b":
+			return false
+		}
+		fmt.Printf("%sPlease answer 'y' or 'n'.%s\n", constants.ColorGray, constants.ColorReset)
+	}
+}
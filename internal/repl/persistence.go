@@ -3,7 +3,10 @@ package repl
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"time"
 
 	"github.com/voidarchive/pokedex/internal/pokeapi"
 	"github.com/voidarchive/pokedex/internal/shared/constants"
@@ -11,25 +14,165 @@ import (
 
 const pokedexFilePath = "pokedex.json"
 
+// saveVersion is the version of the save format written by savePokedex. Version 1 added
+// happiness, version 2 lists owned Pokemon by UID instead of mapping species names to
+// them, version 3 added the seen and caught species of the Pokedex, version 4 added
+// the PC boxes, version 5 saves the party as UIDs instead of copies of its Pokemon and
+// version 6 added the inventory. Older saves have no version.
+const saveVersion = 6
+
 // SaveData encapsulates all data that needs to be persisted.
 type SaveData struct {
-	PokedexData map[string]pokeapi.UserPokemon `json:"pokedex"`
-	PartyData   []pokeapi.UserPokemon          `json:"party"`
+	Version int                   `json:"version,omitempty"`
+	Pokemon []pokeapi.UserPokemon `json:"pokemon,omitempty"` // Every owned Pokemon, ordered by UID
+	// PokedexData holds the owned Pokemon of saves before version 2, by species name.
+	PokedexData map[string]pokeapi.UserPokemon `json:"pokedex,omitempty"`
+	Party       []int                          `json:"party_uids"` // UIDs of the party members, in slot order
+	// PartyData holds copies of the party members in saves before version 5.
+	PartyData []pokeapi.UserPokemon `json:"party,omitempty"`
+	Region    string                `json:"region,omitempty"`
+	Location  string                `json:"location,omitempty"`
+	// SimulatedTime is the time of a simulated clock; games on the wall clock leave it out.
+	SimulatedTime *time.Time `json:"simulated_time,omitempty"`
+	NextUID       int        `json:"next_uid,omitempty"`
+	Seen          []string   `json:"seen,omitempty"`   // Species seen, by name
+	Caught        []string   `json:"caught,omitempty"` // Species caught, by name
+	Boxes         []Box      `json:"boxes,omitempty"`
+	// Inventory is saved even when empty: only saves before version 6 leave it out, and
+	// those start with the items of a new game.
+	Inventory map[string]int `json:"inventory"`
+}
+
+// newSaveData collects what gets persisted from cfg.
+func newSaveData(cfg *Config) SaveData {
+	data := SaveData{
+		Version:   saveVersion,
+		Pokemon:   ownedPokemon(cfg),
+		Party:     cfg.Party,
+		Region:    cfg.CurrentRegion,
+		Location:  cfg.CurrentLocation,
+		NextUID:   cfg.NextUID,
+		Seen:      cfg.Dex.seenSpecies(),
+		Caught:    cfg.Dex.caughtSpecies(),
+		Boxes:     cfg.Boxes,
+		Inventory: cfg.Inventory,
+	}
+	if cfg.Clock != nil && cfg.Clock.Simulated() {
+		now := cfg.Clock.Now()
+		data.SimulatedTime = &now
+	}
+	return data
 }
 
-// savePokedex serializes the user's Pokedex and Party to a JSON file.
-// This is typically called when the application exits.
-func savePokedex(pokedex map[string]pokeapi.UserPokemon, party []pokeapi.UserPokemon) error {
-	saveFile := SaveData{
-		PokedexData: pokedex,
-		PartyData:   party,
+// apply restores the saved game into cfg, migrating saves made by older versions.
+func (data SaveData) apply(cfg *Config) {
+	cfg.Collection = make(map[int]pokeapi.UserPokemon, len(data.Pokemon))
+	for _, p := range data.Pokemon {
+		cfg.Collection[p.UID] = p
+	}
+	if data.Party != nil {
+		cfg.Party = data.Party
+	}
+	cfg.CurrentRegion = data.Region
+	cfg.CurrentLocation = data.Location
+	if data.SimulatedTime != nil {
+		cfg.Clock = NewSimulatedClock(*data.SimulatedTime)
+	}
+	cfg.NextUID = data.NextUID
+	for uid := range cfg.Collection {
+		cfg.NextUID = max(cfg.NextUID, uid+1)
+	}
+	cfg.Dex = newDex()
+	for _, species := range data.Seen {
+		cfg.Dex.Seen[species] = true
+	}
+	for _, species := range data.Caught {
+		cfg.Dex.Caught[species] = true
+	}
+	cfg.Boxes = newBoxes()
+	if data.Boxes != nil {
+		cfg.Boxes = data.Boxes
+	}
+	if data.Inventory != nil {
+		cfg.Inventory = data.Inventory
+	}
+
+	if data.Version < 2 {
+		migrateSpeciesKeyedPokedex(cfg, data.PokedexData, data.PartyData)
 	}
+	if data.Version < 5 {
+		migratePartyCopies(cfg, data.PartyData)
+	}
+	if data.Version < 3 {
+		// The Pokedex didn't record species yet: register those the player owns and
+		// the ones they evolved from.
+		for _, p := range cfg.Collection {
+			cfg.Dex.Seen[p.SpeciesName()], cfg.Dex.Caught[p.SpeciesName()] = true, true
+			for _, evolution := range p.Evolutions {
+				cfg.Dex.Seen[evolution.From], cfg.Dex.Caught[evolution.From] = true, true
+			}
+		}
+	}
+	if data.Version < 1 {
+		// Pokemon didn't have happiness yet: start them off at the usual base happiness.
+		for uid, p := range cfg.Collection {
+			p.Happiness = pokeapi.DefaultHappiness
+			cfg.Collection[uid] = p
+		}
+	}
+	// Before version 4 the Pokemon outside the party had no box: this boxes them.
+	repairStorage(cfg)
+}
+
+// migrateSpeciesKeyedPokedex moves the Pokemon of a save from before version 2, kept by
+// species name, into the collection. Pokemon from saves made before they had a UID get
+// one, and the party copies get the UID of their Pokedex entry.
+func migrateSpeciesKeyedPokedex(cfg *Config, pokedex map[string]pokeapi.UserPokemon, party []pokeapi.UserPokemon) {
+	for _, p := range pokedex {
+		cfg.NextUID = max(cfg.NextUID, p.UID+1)
+	}
+	// Go in name order so the same save always gets the same UIDs.
+	names := slices.Sorted(maps.Keys(pokedex))
+	uids := make(map[string]int, len(names))
+	for _, name := range names {
+		p := pokedex[name]
+		if p.UID == 0 {
+			p.UID = cfg.newUID()
+		}
+		cfg.Collection[p.UID] = p
+		uids[name] = p.UID
+	}
+	for i, p := range party {
+		if uid, ok := uids[p.Name]; ok && p.UID == 0 {
+			party[i].UID = uid
+		}
+	}
+}
+
+// migratePartyCopies turns the party of a save from before version 5, kept as copies
+// of its Pokemon, into UIDs. The collection holds the same Pokemon, so it wins; a party
+// member missing from it is added with a new UID rather than lost.
+func migratePartyCopies(cfg *Config, party []pokeapi.UserPokemon) {
+	cfg.Party = make([]int, 0, len(party))
+	for _, p := range party {
+		if _, owned := cfg.Collection[p.UID]; !owned || p.UID == 0 {
+			p.UID = cfg.newUID()
+			cfg.Collection[p.UID] = p
+		}
+		cfg.Party = append(cfg.Party, p.UID)
+	}
+}
+
+// savePokedex serializes the user's game (owned Pokemon, Party, items and whereabouts) to the JSON
+// file at path. This is typically called when the application exits.
+func savePokedex(path string, cfg *Config) error {
+	saveFile := newSaveData(cfg)
 	data, err := json.MarshalIndent(saveFile, "", "  ")
 	if err != nil {
 		return fmt.Errorf("%sfailed to marshal save data: %w%s", constants.ColorRed, err, constants.ColorReset)
 	}
 
-	err = os.WriteFile(pokedexFilePath, data, 0644) // 0644 provides read/write for owner, read for others.
+	err = os.WriteFile(path, data, 0644) // 0644 provides read/write for owner, read for others.
 	if err != nil {
 		return fmt.Errorf("%sfailed to write save file: %w%s", constants.ColorRed, err, constants.ColorReset)
 	}
@@ -37,38 +180,32 @@ func savePokedex(pokedex map[string]pokeapi.UserPokemon, party []pokeapi.UserPok
 	return nil
 }
 
-// loadPokedex deserializes the Pokedex and Party from a JSON file.
-// If the file doesn't exist or is malformed, it returns an empty Pokedex and Party.
-func loadPokedex() (map[string]pokeapi.UserPokemon, []pokeapi.UserPokemon, error) {
-	pokedex := make(map[string]pokeapi.UserPokemon)
-	var party []pokeapi.UserPokemon
+// loadPokedex deserializes the game saved in the JSON file at path.
+// If the file doesn't exist or is empty, it returns an empty game.
+func loadPokedex(path string) (SaveData, error) {
+	var saveData SaveData
 
-	data, err := os.ReadFile(pokedexFilePath)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			fmt.Printf("%sNo saved data found (%s). Starting fresh.%s\n", constants.ColorYellow, pokedexFilePath, constants.ColorReset)
-			return pokedex, party, nil // Return empty structures, no error for non-existence
+			fmt.Printf("%sNo saved data found (%s). Starting fresh.%s\n", constants.ColorYellow, path, constants.ColorReset)
+			return saveData, nil // Return empty structures, no error for non-existence
 		}
-		return nil, nil, fmt.Errorf("%sfailed to read save file: %w%s", constants.ColorRed, err, constants.ColorReset)
+		return SaveData{}, fmt.Errorf("%sfailed to read save file: %w%s", constants.ColorRed, err, constants.ColorReset)
 	}
 
 	if len(data) == 0 {
-		fmt.Printf("%sSave file (%s) is empty. Starting fresh.%s\n", constants.ColorYellow, pokedexFilePath, constants.ColorReset)
-		return pokedex, party, nil // Return empty structures, no error for empty file
+		fmt.Printf("%sSave file (%s) is empty. Starting fresh.%s\n", constants.ColorYellow, path, constants.ColorReset)
+		return saveData, nil // Return empty structures, no error for empty file
 	}
 
-	var saveData SaveData
 	err = json.Unmarshal(data, &saveData)
 	if err != nil {
-		return nil, nil, fmt.Errorf("%sfailed to unmarshal save data: %w%s", constants.ColorRed, err, constants.ColorReset)
+		return SaveData{}, fmt.Errorf("%sfailed to unmarshal save data: %w%s", constants.ColorRed, err, constants.ColorReset)
 	}
 
-	// Ensure Pokedex isn't nil if it was missing in JSON, though MarshalIndent should handle empty maps.
-	if saveData.PokedexData == nil {
-		saveData.PokedexData = make(map[string]pokeapi.UserPokemon)
-	}
 	// Party can be nil if empty, which is fine for an empty slice.
 
-	fmt.Printf("%sGame data loaded from %s%s%s\n", constants.ColorGreen, constants.ColorYellow, pokedexFilePath, constants.ColorReset)
-	return saveData.PokedexData, saveData.PartyData, nil
+	fmt.Printf("%sGame data loaded from %s%s%s\n", constants.ColorGreen, constants.ColorYellow, path, constants.ColorReset)
+	return saveData, nil
 }
@@ -0,0 +1,220 @@
+package repl
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/shared/constants"
+)
+
+// queryNumberFields are the numeric fields a query can filter and sort on, besides the
+// base stats: "stat." and a name of queryStats, e.g. "stat.speed".
+var queryNumberFields = map[string]func(p pokeapi.UserPokemon) int{
+	"id":        func(p pokeapi.UserPokemon) int { return p.UID },
+	"level":     func(p pokeapi.UserPokemon) int { return p.Level },
+	"xp":        func(p pokeapi.UserPokemon) int { return p.CurrentXP },
+	"happiness": func(p pokeapi.UserPokemon) int { return p.Happiness },
+}
+
+// queryStats are the base stats, by the names PokeAPI uses.
+var queryStats = []string{"hp", "attack", "defense", "special-attack", "special-defense", "speed"}
+
+// queryTextFields are the text fields a query can filter and sort on. Catch dates read
+// "2006-01-02", so they compare in calendar order.
+var queryTextFields = map[string]func(p pokeapi.UserPokemon) string{
+	"name":     func(p pokeapi.UserPokemon) string { return p.DisplayName() },
+	"nickname": func(p pokeapi.UserPokemon) string { return p.Nickname },
+	"species":  func(p pokeapi.UserPokemon) string { return p.Name },
+	"item":     func(p pokeapi.UserPokemon) string { return p.HeldItem },
+	"gender":   func(p pokeapi.UserPokemon) string { return p.Gender },
+	"type":     func(p pokeapi.UserPokemon) string { return strings.Join(typeNames(p.PokemonData), "/") },
+	"caught":   func(p pokeapi.UserPokemon) string { return caughtDate(p) },
+}
+
+// queryOperators are the comparisons a filter can make, two-character ones first so
+// that "level>=10" isn't read as "level>" "=10".
+var queryOperators = []string{">=", "<=", "!=", ">", "<", "=", ":"}
+
+// queryFilter is one term of a query, e.g. "level>10": field, operator and value.
+type queryFilter struct {
+	field, op, value string
+}
+
+// pokemonQuery selects and orders owned Pokemon. It is read from terms like
+// "type:fire level>10 stat.speed>=90", which must all hold, and "--sort level desc".
+type pokemonQuery struct {
+	filters []queryFilter
+	sortBy  string
+	desc    bool
+}
+
+// parseQuery reads a query from the arguments of the 'pokemon' command.
+func parseQuery(args []string) (pokemonQuery, error) {
+	query := pokemonQuery{sortBy: "id"}
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--sort" {
+			if i+1 >= len(args) {
+				return pokemonQuery{}, fmt.Errorf("%s--sort needs a field, e.g. '--sort level desc'%s", constants.ColorYellow, constants.ColorReset)
+			}
+			query.sortBy = args[i+1]
+			i++
+			if i+1 < len(args) && (args[i+1] == "asc" || args[i+1] == "desc") {
+				query.desc = args[i+1] == "desc"
+				i++
+			}
+			if err := checkQueryField(query.sortBy); err != nil {
+				return pokemonQuery{}, err
+			}
+			continue
+		}
+
+		filter, err := parseQueryFilter(args[i])
+		if err != nil {
+			return pokemonQuery{}, err
+		}
+		query.filters = append(query.filters, filter)
+	}
+	return query, nil
+}
+
+// parseQueryFilter reads a term like "stat.speed>=90".
+func parseQueryFilter(term string) (queryFilter, error) {
+	at := strings.IndexAny(term, "<>=!:")
+	if at <= 0 {
+		return queryFilter{}, fmt.Errorf("%scan't read '%s%s%s': filters look like 'type:fire', 'level>10' or 'stat.speed>=90'%s", constants.ColorYellow, constants.ColorBrightRed, term, constants.ColorYellow, constants.ColorReset)
+	}
+	filter := queryFilter{field: term[:at]}
+	for _, op := range queryOperators {
+		if strings.HasPrefix(term[at:], op) {
+			filter.op, filter.value = op, term[at+len(op):]
+			break
+		}
+	}
+	if filter.op == "" || filter.value == "" {
+		return queryFilter{}, fmt.Errorf("%scan't read '%s%s%s': filters look like 'type:fire', 'level>10' or 'stat.speed>=90'%s", constants.ColorYellow, constants.ColorBrightRed, term, constants.ColorYellow, constants.ColorReset)
+	}
+	if err := checkQueryField(filter.field); err != nil {
+		return queryFilter{}, err
+	}
+	if isNumberField(filter.field) {
+		if _, err := strconv.Atoi(filter.value); err != nil {
+			return queryFilter{}, fmt.Errorf("%s%s%s%s needs a number, not '%s'%s", constants.ColorBrightRed, filter.field, constants.ColorYellow, filter.op, filter.value, constants.ColorReset)
+		}
+	}
+	return filter, nil
+}
+
+// checkQueryField returns an error for a field queries don't know.
+func checkQueryField(field string) error {
+	if _, ok := queryTextFields[field]; ok || isNumberField(field) {
+		return nil
+	}
+	fields := slices.Concat(slices.Collect(maps.Keys(queryNumberFields)), slices.Collect(maps.Keys(queryTextFields)))
+	for _, stat := range queryStats {
+		fields = append(fields, "stat."+stat)
+	}
+	slices.Sort(fields)
+	return fmt.Errorf("%sunknown field '%s%s%s'. Fields: %s%s", constants.ColorYellow, constants.ColorBrightRed, field, constants.ColorYellow, strings.Join(fields, ", "), constants.ColorReset)
+}
+
+func isNumberField(field string) bool {
+	stat, isStat := strings.CutPrefix(field, "stat.")
+	_, ok := queryNumberFields[field]
+	return ok || isStat && slices.Contains(queryStats, stat)
+}
+
+// numberField returns a numeric field of p.
+func numberField(p pokeapi.UserPokemon, field string) int {
+	if stat, ok := strings.CutPrefix(field, "stat."); ok {
+		value, _ := p.GetStat(stat)
+		return value
+	}
+	return queryNumberFields[field](p)
+}
+
+// matches reports whether p passes every filter of the query.
+func (q pokemonQuery) matches(p pokeapi.UserPokemon) bool {
+	for _, f := range q.filters {
+		if !f.matches(p) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f queryFilter) matches(p pokeapi.UserPokemon) bool {
+	if isNumberField(f.field) {
+		want, _ := strconv.Atoi(f.value)
+		return compareWith(f.op, cmp.Compare(numberField(p, f.field), want))
+	}
+
+	// A Pokemon with two types matches a type filter if either type does.
+	values := []string{queryTextFields[f.field](p)}
+	if f.field == "type" {
+		values = typeNames(p.PokemonData)
+	}
+	if f.op == "!=" {
+		return !slices.Contains(values, f.value)
+	}
+	for _, value := range values {
+		if f.op == ":" && strings.Contains(value, f.value) || f.op != ":" && compareWith(f.op, cmp.Compare(value, f.value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareWith reports whether a comparison result c satisfies op.
+func compareWith(op string, c int) bool {
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "!=":
+		return c != 0
+	default: // "=" and ":"
+		return c == 0
+	}
+}
+
+// run returns the Pokemon that match the query, sorted. Ties keep ID order.
+func (q pokemonQuery) run(pokemon []pokeapi.UserPokemon) []pokeapi.UserPokemon {
+	var results []pokeapi.UserPokemon
+	for _, p := range pokemon {
+		if q.matches(p) {
+			results = append(results, p)
+		}
+	}
+	slices.SortStableFunc(results, func(a, b pokeapi.UserPokemon) int {
+		var c int
+		if isNumberField(q.sortBy) {
+			c = cmp.Compare(numberField(a, q.sortBy), numberField(b, q.sortBy))
+		} else {
+			c = cmp.Compare(queryTextFields[q.sortBy](a), queryTextFields[q.sortBy](b))
+		}
+		if q.desc {
+			return -c
+		}
+		return c
+	})
+	return results
+}
+
+// caughtDate is the day a Pokemon was caught, e.g. "2024-05-01", or "" if unknown.
+func caughtDate(p pokeapi.UserPokemon) string {
+	if p.CaughtTimestamp == 0 {
+		return ""
+	}
+	return time.Unix(0, p.CaughtTimestamp).Format(time.DateOnly)
+}
@@ -0,0 +1,84 @@
+package repl
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/pokeapi/pokeapitest"
+)
+
+func queryPokemon() []pokeapi.UserPokemon {
+	owned := []struct {
+		data     pokeapi.PokemonData
+		level    int
+		nickname string
+		caught   time.Time
+	}{
+		{pokeapitest.NewPokemon(4, "charmander", 62, pokeapitest.Stats{HP: 39, Attack: 52, Defense: 43, SpecialAttack: 60, SpecialDefense: 50, Speed: 65}, "fire"), 12, "", time.Date(2024, time.May, 1, 10, 0, 0, 0, time.Local)},
+		{pokeapitest.NewPokemon(6, "charizard", 240, pokeapitest.Stats{HP: 78, Attack: 84, Defense: 78, SpecialAttack: 109, SpecialDefense: 85, Speed: 100}, "fire", "flying"), 40, "blaze", time.Date(2024, time.May, 3, 10, 0, 0, 0, time.Local)},
+		{pokeapitest.NewPokemon(25, "pikachu", 112, pokeapitest.Stats{HP: 35, Attack: 55, Defense: 40, SpecialAttack: 50, SpecialDefense: 50, Speed: 90}, "electric"), 8, "sparky", time.Date(2024, time.June, 1, 10, 0, 0, 0, time.Local)},
+		{pokeapitest.NewPokemon(16, "pidgey", 50, pokeapitest.Stats{HP: 40, Attack: 45, Defense: 40, SpecialAttack: 35, SpecialDefense: 35, Speed: 56}, "normal", "flying"), 5, "", time.Date(2024, time.June, 2, 10, 0, 0, 0, time.Local)},
+	}
+	pokemon := make([]pokeapi.UserPokemon, len(owned))
+	for i, o := range owned {
+		pokemon[i] = pokeapi.NewUserPokemon(o.data, o.level)
+		pokemon[i].UID = i + 1
+		pokemon[i].Nickname = o.nickname
+		pokemon[i].CaughtTimestamp = o.caught.UnixNano()
+	}
+	return pokemon
+}
+
+func TestPokemonQuery(t *testing.T) {
+	tests := []struct {
+		query []string
+		want  []int // UIDs, in order
+	}{
+		{query: nil, want: []int{1, 2, 3, 4}},
+		{query: []string{"type:fire"}, want: []int{1, 2}},
+		{query: []string{"type:flying", "level>10"}, want: []int{2}},
+		{query: []string{"type!=flying"}, want: []int{1, 3}},
+		{query: []string{"stat.speed>=90"}, want: []int{2, 3}},
+		{query: []string{"level<=8", "--sort", "level"}, want: []int{4, 3}},
+		{query: []string{"--sort", "level", "desc"}, want: []int{2, 1, 3, 4}},
+		{query: []string{"--sort", "stat.attack", "asc"}, want: []int{4, 1, 3, 2}},
+		{query: []string{"name:char"}, want: []int{1}},
+		{query: []string{"species:char"}, want: []int{1, 2}},
+		{query: []string{"nickname=sparky"}, want: []int{3}},
+		{query: []string{"caught>=2024-05-03", "caught<2024-06-02"}, want: []int{2, 3}},
+		{query: []string{"caught:2024-06", "--sort", "name", "desc"}, want: []int{3, 4}},
+	}
+
+	for _, tc := range tests {
+		query, err := parseQuery(tc.query)
+		if err != nil {
+			t.Fatalf("parseQuery(%q) returned error: %v", tc.query, err)
+		}
+		var got []int
+		for _, p := range query.run(queryPokemon()) {
+			got = append(got, p.UID)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("query %q = %v; want %v", tc.query, got, tc.want)
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, query := range [][]string{
+		{"fire"},
+		{"level>"},
+		{":fire"},
+		{"level>ten"},
+		{"color:red"},
+		{"stat.luck>5"},
+		{"--sort"},
+		{"--sort", "weight"},
+	} {
+		if _, err := parseQuery(query); err == nil {
+			t.Errorf("parseQuery(%q) should fail", query)
+		}
+	}
+}
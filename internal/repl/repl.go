@@ -3,6 +3,7 @@ package repl
 import (
 	"fmt"
 	"io"
+	"log/slog"
 	"math/rand"
 	"os"
 	"strings"
@@ -35,40 +36,141 @@ func getCommands() map[string]cliCommand {
 			Description: "Displays the previous 20 Pokemon location areas",
 			Callback:    commandMapb,
 		},
+		"regions": {
+			Name:        "regions",
+			Description: "List the regions you can travel to",
+			Callback:    commandRegions,
+		},
+		"travel": {
+			Name:        "travel <region|location|number>",
+			Description: "Travel to a region, or to a location in it along the shortest route",
+			Callback:    commandTravel,
+			Action:      true,
+		},
+		"move": {
+			Name:        "move <direction|location>",
+			Description: "Follow a path to a neighboring location, e.g. 'move north'",
+			Callback:    commandMove,
+			Action:      true,
+		},
+		"where": {
+			Name:        "where",
+			Description: "Show where you are and the paths leading away",
+			Callback:    commandWhere,
+		},
+		"areas": {
+			Name:        "areas",
+			Description: "List the areas of your current location",
+			Callback:    commandAreas,
+		},
 		"explore": {
-			Name:        "explore <location_area_name>",
-			Description: "Explore a location area for Pokemon",
+			Name:        "explore <location_area_name> [--method walk|surf|old-rod|...]",
+			Description: "Explore an area of your current location and run into a wild Pokemon",
 			Callback:    commandExplore,
+			Action:      true,
 		},
 		"catch": {
-			Name:        "catch <pokemon_name>",
-			Description: "Attempt to catch a Pokemon",
+			Name:        "catch [pokemon_name] [pokeball_type]",
+			Description: "Throw a ball at the wild Pokemon you're facing",
 			Callback:    commandCatch,
+			Action:      true,
+		},
+		"run": {
+			Name:        "run",
+			Description: "Run away from the wild Pokemon you're facing",
+			Callback:    commandRun,
+			Action:      true,
 		},
 		"inspect": {
 			Name:        "inspect <pokemon_name>",
 			Description: "View details of a caught Pokemon",
 			Callback:    commandInspect,
 		},
+		"nickname": {
+			Name:        "nickname <pokemon> [nickname]",
+			Description: "Give a caught Pokemon a nickname, or remove it",
+			Callback:    commandNickname,
+		},
+		"evolutions": {
+			Name:        "evolutions <pokemon_name>",
+			Description: "Show a Pokemon's evolution chain and how each stage evolves",
+			Callback:    commandEvolutions,
+		},
 		"pokedex": {
-			Name:        "pokedex",
-			Description: "View all Pokemon in your Pokedex",
+			Name:        "pokedex [stats|<pokedex_or_region>]",
+			Description: "View the species you've seen and caught, in national or regional order, or how complete your Pokedex is",
 			Callback:    commandPokedex,
 		},
+		"pokemon": {
+			Name:        "pokemon [filter...] [--sort <field> [asc|desc]]",
+			Description: "List the Pokemon you own as a table, e.g. 'pokemon type:fire level>10 --sort level desc'",
+			Callback:    commandPokemon,
+		},
 		"party": {
-			Name:        "party",
-			Description: "View the Pokemon in your active party",
+			Name:        "party [swap <slot> <slot>|move <slot> <slot>|remove <slot>|lead <slot>]",
+			Description: "View the Pokemon in your active party, reorder it, put one in the PC or pick your lead",
 			Callback:    commandParty,
 		},
+		"release": {
+			Name:        "release <pokemon>",
+			Description: "Let one of your Pokemon go for good",
+			Callback:    commandRelease,
+		},
+		"deposit": {
+			Name:        "deposit <pokemon> [box]",
+			Description: "Store a party Pokemon in a PC box",
+			Callback:    commandDeposit,
+		},
+		"withdraw": {
+			Name:        "withdraw <pokemon>",
+			Description: "Take a Pokemon out of its PC box and into your party",
+			Callback:    commandWithdraw,
+		},
+		"box": {
+			Name:        "box [list|<box>|rename <box> <name>|move <pokemon> <box>]",
+			Description: "List your PC boxes, see what's in one, rename one or move a Pokemon to one",
+			Callback:    commandBox,
+		},
 		"inventory": {
 			Name:        "inventory",
 			Description: "View your items, including Pokeballs",
 			Callback:    commandInventory,
 		},
 		"battle": {
-			Name:        "battle <your_pokemon> <opponent_pokemon>",
-			Description: "Simulate a battle between one of your Pokemon and an opponent",
+			Name:        "battle [your_pokemon] [opponent_pokemon]",
+			Description: "Fight a round against the wild Pokemon you're facing, with your party's lead if you name no Pokemon, or simulate a full battle against an opponent",
 			Callback:    commandBattle,
+			Action:      true,
+		},
+		"use": {
+			Name:        "use <item> <pokemon_name>",
+			Description: "Use an item on a Pokemon, e.g. an evolution stone",
+			Callback:    commandUse,
+		},
+		"give": {
+			Name:        "give <item> <pokemon_name>",
+			Description: "Let a Pokemon hold an item, e.g. an everstone to keep it from evolving",
+			Callback:    commandGive,
+		},
+		"take": {
+			Name:        "take <pokemon_name>",
+			Description: "Take back the item a Pokemon is holding",
+			Callback:    commandTake,
+		},
+		"trade": {
+			Name:        "trade <pokemon_name> [for <species>]",
+			Description: "Link-trade a party Pokemon with a partner who trades it back, for trade evolutions",
+			Callback:    commandTrade,
+		},
+		"time": {
+			Name:        "time [set <HH:MM>|wall]",
+			Description: "Show the in-game time, or switch between a simulated clock and your computer's clock",
+			Callback:    commandTime,
+		},
+		"debug": {
+			Name:        "debug <on|off>",
+			Description: "Show or hide the API request and cache trace",
+			Callback:    commandDebug,
 		},
 	}
 }
@@ -77,36 +179,57 @@ func stringToPtr(s string) *string {
 	return &s
 }
 
-func StartRepl(pokeapiClient PokeapiClient, cache Pokecache) {
-	loadedPokedex, loadedParty, err := loadPokedex()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "%sError loading saved data: %v. Starting fresh.%s\n", constants.ColorRed, err, constants.ColorReset)
-		loadedPokedex = make(map[string]pokeapi.UserPokemon)
-		loadedParty = []pokeapi.UserPokemon{}
-	} else {
-		if loadedParty == nil {
-			loadedParty = []pokeapi.UserPokemon{}
-		}
-	}
-
-	initialInventory := map[string]int{
-		"pokeball":  10,
-		"greatball": 5,
-	}
-
-	cfg := &Config{
+// NewConfig returns the state of a fresh game: an empty Pokedex and party,
+// the starting inventory, and the first page of location areas queued up for 'map'.
+func NewConfig(pokeapiClient PokeapiClient, cache Pokecache, randomizer *rand.Rand) *Config {
+	return &Config{
 		NextLocationAreaURL: stringToPtr(pokeapi.BaseURL + "/location-area"),
 		PrevLocationAreaURL: nil,
 		PokeapiClient:       pokeapiClient,
 		Cache:               cache,
-		Pokedex:             loadedPokedex,
-		Party:               loadedParty,
-		Inventory:           initialInventory,
-		Randomizer:          rand.New(rand.NewSource(time.Now().UnixNano())),
+		Collection:          make(map[int]pokeapi.UserPokemon),
+		Dex:                 newDex(),
+		Boxes:               newBoxes(),
+		Party:               []int{},
+		Inventory: map[string]int{
+			"pokeball":  10,
+			"greatball": 5,
+			"timerball": 2,
+			"quickball": 2,
+			"netball":   2,
+			"duskball":  2,
+			// Evolution stones for 'use', and an Everstone to 'give' to a Pokemon that
+			// shouldn't evolve.
+			"fire-stone":    1,
+			"water-stone":   1,
+			"thunder-stone": 1,
+			"leaf-stone":    1,
+			"moon-stone":    1,
+			"everstone":     1,
+			// Berries for 'use' and a Soothe Bell to 'give', to make Pokemon happier.
+			"pomeg-berry": 3,
+			"soothe-bell": 1,
+		},
+		Randomizer: randomizer,
+		Clock:      NewWallClock(),
+	}
+}
+
+// StartRepl runs the interactive prompt. logLevel controls the request/cache trace
+// logger and is what the 'debug' command toggles; it may be nil.
+func StartRepl(pokeapiClient PokeapiClient, cache Pokecache, logLevel *slog.LevelVar) {
+	cfg := NewConfig(pokeapiClient, cache, rand.New(rand.NewSource(time.Now().UnixNano())))
+	cfg.LogLevel = logLevel
+
+	saveData, err := loadPokedex(pokedexFilePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%sError loading saved data: %v. Starting fresh.%s\n", constants.ColorRed, err, constants.ColorReset)
+	} else {
+		saveData.apply(cfg)
 	}
 
 	rl, err := readline.NewEx(&readline.Config{
-		Prompt:          fmt.Sprintf("%sPokedex > %s", constants.ColorCyan, constants.ColorReset),
+		Prompt:          prompt(cfg),
 		HistoryFile:     "/tmp/pokedex_history.tmp",
 		InterruptPrompt: "^C",
 		EOFPrompt:       "exit",
@@ -116,19 +239,20 @@ func StartRepl(pokeapiClient PokeapiClient, cache Pokecache) {
 		return
 	}
 	defer rl.Close()
+	cfg.Input = readlineInput{rl}
 
-	commands := getCommands()
 	for {
+		rl.SetPrompt(prompt(cfg))
 		line, err := rl.Readline()
 		if err == readline.ErrInterrupt { // Ctrl+C
 			fmt.Printf("\n%sInterrupt received, saving game data and exiting...%s\n", constants.ColorYellow, constants.ColorReset)
-			if saveErr := savePokedex(cfg.Pokedex, cfg.Party); saveErr != nil {
+			if saveErr := savePokedex(pokedexFilePath, cfg); saveErr != nil {
 				fmt.Fprintf(os.Stderr, "%sError saving game data on interrupt: %v%s\n", constants.ColorRed, saveErr, constants.ColorReset)
 			}
 			return
 		} else if err == io.EOF { // Ctrl+D
 			fmt.Printf("\n%sEOF received, saving game data and exiting...%s\n", constants.ColorYellow, constants.ColorReset)
-			if saveErr := savePokedex(cfg.Pokedex, cfg.Party); saveErr != nil {
+			if saveErr := savePokedex(pokedexFilePath, cfg); saveErr != nil {
 				fmt.Fprintf(os.Stderr, "%sError saving game data on EOF: %v%s\n", constants.ColorRed, saveErr, constants.ColorReset)
 			}
 			return
@@ -137,31 +261,42 @@ func StartRepl(pokeapiClient PokeapiClient, cache Pokecache) {
 			continue
 		}
 
-		if line == "" {
-			continue
+		if err := ExecuteLine(cfg, line); err != nil {
+			PrintError(err)
 		}
+	}
+}
 
-		userInput := CleanInput(line)
-		if len(userInput) == 0 {
-			continue
-		}
+// ExecuteLine runs one line of user input against cfg, exactly as if it had been typed
+// at the prompt. Blank lines are ignored. Command errors are returned, not printed.
+func ExecuteLine(cfg *Config, line string) error {
+	userInput := CleanInput(line)
+	if len(userInput) == 0 {
+		return nil
+	}
 
-		commandName := userInput[0]
-		args := []string{}
-		if len(userInput) > 1 {
-			args = userInput[1:]
-		}
+	commandName := userInput[0]
+	args := []string{}
+	if len(userInput) > 1 {
+		args = userInput[1:]
+	}
 
-		command, exists := commands[commandName]
-		if exists {
-			err := command.Callback(cfg, args...)
-			if err != nil {
-				fmt.Printf("%s%v%s\n", constants.ColorRed, err, constants.ColorReset)
-			}
-		} else {
-			fmt.Printf("%sUnknown command: %s%s%s\n", constants.ColorRed, commandName, constants.ColorRed, constants.ColorReset)
-		}
+	command, exists := getCommands()[commandName]
+	if !exists {
+		return fmt.Errorf("%sUnknown command: %s%s%s", constants.ColorRed, commandName, constants.ColorRed, constants.ColorReset)
+	}
+	if err := command.Callback(cfg, args...); err != nil {
+		return err
 	}
+	if command.Action && cfg.Clock != nil {
+		cfg.Clock.Advance(1)
+	}
+	return nil
+}
+
+// PrintError shows a command error the way the REPL does.
+func PrintError(err error) {
+	fmt.Printf("%s%v%s\n", constants.ColorRed, err, constants.ColorReset)
 }
 
 func CleanInput(text string) []string {
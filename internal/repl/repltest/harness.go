@@ -0,0 +1,119 @@
+// Package repltest runs scripted REPL sessions against a repl.Config and captures
+// what the player would have seen, for end-to-end scenario tests.
+package repltest
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/voidarchive/pokedex/internal/pokecache"
+	"github.com/voidarchive/pokedex/internal/repl"
+)
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// NewConfig returns a fresh-game Config backed by client with a randomizer seeded
+// with seed, so a script produces the same transcript on every run.
+func NewConfig(client repl.PokeapiClient, seed int64) *repl.Config {
+	return repl.NewConfig(client, pokecache.NewCache(5*time.Minute), rand.New(rand.NewSource(seed)))
+}
+
+// Run executes each line against cfg as if it had been typed at the prompt and returns
+// the transcript: every line echoed after a "Pokedex > " prompt, followed by everything
+// the command printed to stdout. Command errors appear in the transcript the same way
+// the REPL shows them. ANSI colors are stripped so tests can match plain text.
+//
+// A command that asks a question, like the evolution prompt, reads its answer from the
+// next line of the script; the answer is echoed after the question.
+//
+// The 'exit' command terminates the process, so scripts must not use it.
+func Run(t testing.TB, cfg *repl.Config, lines ...string) string {
+	t.Helper()
+
+	for _, line := range lines {
+		if fields := repl.CleanInput(line); len(fields) > 0 && fields[0] == "exit" {
+			t.Fatalf("repltest: scripts cannot run 'exit', it would stop the test binary")
+		}
+	}
+
+	script := &scriptInput{lines: lines}
+	cfg.Input = script
+	output := captureStdout(t, func() {
+		for len(script.lines) > 0 {
+			line := script.next()
+			os.Stdout.WriteString("Pokedex > " + line + "\n")
+			if err := repl.ExecuteLine(cfg, line); err != nil {
+				repl.PrintError(err)
+			}
+		}
+	})
+	return ansiEscape.ReplaceAllString(output, "")
+}
+
+// scriptInput answers prompts with the next lines of a script.
+type scriptInput struct {
+	lines []string
+}
+
+func (s *scriptInput) next() string {
+	line := s.lines[0]
+	s.lines = s.lines[1:]
+	return line
+}
+
+func (s *scriptInput) ReadLine(prompt string) (string, error) {
+	if len(s.lines) == 0 {
+		return "", io.EOF
+	}
+	answer := s.next()
+	os.Stdout.WriteString(prompt + answer + "\n")
+	return answer, nil
+}
+
+// captureStdout redirects os.Stdout to a pipe while fn runs and returns what was written.
+func captureStdout(t testing.TB, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("repltest: could not create pipe: %v", err)
+	}
+
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		done <- buf.String()
+	}()
+
+	original := os.Stdout
+	os.Stdout = w
+	defer func() {
+		os.Stdout = original
+	}()
+
+	fn()
+
+	w.Close()
+	out := <-done
+	r.Close()
+	return out
+}
+
+// Contains reports whether every want string appears in transcript, in order.
+func Contains(transcript string, want ...string) bool {
+	for _, w := range want {
+		i := strings.Index(transcript, w)
+		if i < 0 {
+			return false
+		}
+		transcript = transcript[i+len(w):]
+	}
+	return true
+}
@@ -0,0 +1,1058 @@
+package repl_test
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/voidarchive/pokedex/internal/battle"
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/pokeapi/pokeapitest"
+	"github.com/voidarchive/pokedex/internal/repl"
+	"github.com/voidarchive/pokedex/internal/repl/repltest"
+	"github.com/voidarchive/pokedex/internal/shared/logging"
+)
+
+var _ repl.PokeapiClient = (*pokeapitest.Fake)(nil)
+
+// newScenarioFake seeds a fake PokeAPI with the caterpie line, a bulky, harmless
+// sparring partner that hands out plenty of XP, and kanto locations to meet them in the wild.
+func newScenarioFake() *pokeapitest.Fake {
+	return pokeapitest.NewFake().
+		AddRegion(pokeapi.Region{Name: "kanto"},
+			pokeapitest.Location("viridian-forest", "nursery-area", "viridian-forest-area"),
+			pokeapitest.Location("cerulean-cave", "cerulean-cave-area"),
+			pokeapitest.Location("kanto-route-12", "kanto-route-12-area"),
+			pokeapitest.Location("power-plant", "power-plant-area"),
+			pokeapitest.Location("kanto-route-1", "kanto-route-1-area"),
+			pokeapitest.Location("pallet-town"),
+		).
+		AddLocationArea(pokeapitest.Area("nursery-area", pokeapitest.Encounter("caterpie", 1, 1))).
+		AddLocationArea(pokeapitest.Area("viridian-forest-area", pokeapitest.Encounter("caterpie", 3, 5))).
+		AddLocationArea(pokeapitest.Area("cerulean-cave-area", pokeapitest.Encounter("mewtwo", 70, 70))).
+		AddPokemon(
+			pokeapitest.NewPokemon(10, "caterpie", 39, pokeapitest.Stats{HP: 45, Attack: 30, Defense: 35, SpecialAttack: 20, SpecialDefense: 20, Speed: 45}, "bug"),
+			pokeapitest.NewPokemon(11, "metapod", 72, pokeapitest.Stats{HP: 50, Attack: 20, Defense: 55, SpecialAttack: 25, SpecialDefense: 25, Speed: 30}, "bug"),
+			pokeapitest.NewPokemon(12, "butterfree", 178, pokeapitest.Stats{HP: 60, Attack: 45, Defense: 50, SpecialAttack: 90, SpecialDefense: 80, Speed: 70}, "bug", "flying"),
+			pokeapitest.NewPokemon(113, "chansey", 395, pokeapitest.Stats{HP: 250, Attack: 5, Defense: 5, SpecialAttack: 35, SpecialDefense: 105, Speed: 50}, "normal"),
+			pokeapitest.NewPokemon(150, "mewtwo", 340, pokeapitest.Stats{HP: 106, Attack: 110, Defense: 90, SpecialAttack: 154, SpecialDefense: 90, Speed: 130}, "psychic"),
+		).
+		AddEvolutionChain(pokeapitest.ChainURL(4), pokeapi.EvolutionChainResponse{
+			ID: 4,
+			Chain: pokeapitest.Link("caterpie", nil,
+				pokeapitest.Link("metapod", pokeapitest.LevelUp(7),
+					pokeapitest.Link("butterfree", pokeapitest.LevelUp(10)),
+				),
+			),
+		})
+}
+
+func TestScenarioCatchBattleLevelUpEvolve(t *testing.T) {
+	cfg := repltest.NewConfig(newScenarioFake(), 1)
+
+	transcript := repltest.Run(t, cfg,
+		"travel kanto",
+		"travel viridian-forest",
+		"explore nursery-area",
+		"catch caterpie quickball", // 5x on the first battle turn: a sure catch for caterpie
+		"battle caterpie chansey",
+		"battle caterpie chansey",
+		"battle caterpie chansey",
+		"battle caterpie chansey",
+		"battle caterpie chansey",
+		"battle caterpie chansey",
+		"battle caterpie chansey",
+		"y", // let it evolve
+		"party",
+		"inspect metapod",
+	)
+
+	if !repltest.Contains(transcript,
+		"caterpie was caught!",
+		"caterpie wins!",
+		"grew to Level 2",
+		"Let caterpie evolve? (Y/n) > y",
+		"evolved into metapod by level-up",
+		"Slot 1: #1 metapod (Lvl 7)",
+		"ID: 1",
+		"Evolution history:",
+		"caterpie -> metapod at Lv 7 by level-up",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+
+	if len(cfg.Collection) != 1 {
+		t.Errorf("caterpie should have been replaced by its evolution, have %v", cfg.Collection)
+	}
+	metapod, ok := owned(cfg, "metapod")
+	if !ok {
+		t.Fatalf("expected to own a metapod, have %v", cfg.Collection)
+	}
+	if metapod.Level != 7 {
+		t.Errorf("metapod level = %d; want 7", metapod.Level)
+	}
+	if len(cfg.Party) != 1 || cfg.Collection[cfg.Party[0]].Name != "metapod" {
+		t.Errorf("party should hold the evolved metapod, got %v", cfg.Party)
+	}
+}
+
+func TestScenarioLostBattleGivesNoXP(t *testing.T) {
+	cfg := repltest.NewConfig(newScenarioFake(), 1)
+
+	transcript := repltest.Run(t, cfg,
+		"travel kanto",
+		"travel viridian-forest",
+		"explore nursery-area",
+		"catch caterpie quickball",
+		"battle caterpie mewtwo",
+	)
+
+	if !repltest.Contains(transcript, "caterpie was caught!", "mewtwo wins!") {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+	if strings.Contains(transcript, "gained") {
+		t.Errorf("a lost battle shouldn't award XP:\n%s", transcript)
+	}
+	if got, _ := owned(cfg, "caterpie"); got.Level != 1 || got.CurrentXP != 0 {
+		t.Errorf("caterpie progress changed after a loss: level=%d xp=%d", got.Level, got.CurrentXP)
+	}
+}
+
+func TestScenarioInspectShowsPokedexEntry(t *testing.T) {
+	happiness := 70
+	caterpie := pokeapi.PokemonSpecies{
+		ID:            10,
+		Name:          "caterpie",
+		CaptureRate:   255,
+		BaseHappiness: &happiness,
+		GrowthRate:    pokeapi.NamedAPIResource{Name: "medium"},
+		Generation:    pokeapi.NamedAPIResource{Name: "generation-i"},
+		Habitat:       &pokeapi.NamedAPIResource{Name: "forest"},
+		Genera: []pokeapi.Genus{
+			{Genus: "Worm Pokémon", Language: pokeapi.NamedAPIResource{Name: "en"}},
+		},
+		FlavorTextEntries: []pokeapi.FlavorTextEntry{
+			{FlavorText: "Its short feet are tipped with\fsuction pads.", Language: pokeapi.NamedAPIResource{Name: "en"}, Version: pokeapi.NamedAPIResource{Name: "red"}},
+		},
+	}
+	caterpie.EvolutionChain.URL = pokeapitest.ChainURL(4)
+	cfg := repltest.NewConfig(newScenarioFake().AddSpecies(caterpie), 1)
+
+	transcript := repltest.Run(t, cfg,
+		"travel kanto",
+		"travel viridian-forest",
+		"explore nursery-area",
+		"catch caterpie quickball",
+		"inspect caterpie",
+	)
+
+	if !repltest.Contains(transcript,
+		"--- Inspecting: #1 caterpie ---",
+		"#010 caterpie - the Worm Pokémon",
+		"Its short feet are tipped with suction pads.",
+		"(Pokemon red)",
+		"Generation: I  Habitat: forest  Growth rate: medium",
+		"Capture rate: 255  Base happiness: 70",
+		"Level: 1",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+	if strings.Contains(transcript, "Legendary") {
+		t.Errorf("caterpie shouldn't be tagged legendary:\n%s", transcript)
+	}
+}
+
+func TestScenarioInspectWithoutSpeciesData(t *testing.T) {
+	// No species data registered: inspect falls back to what's stored on the Pokemon.
+	caterpie := pokeapitest.NewPokemon(10, "caterpie", 39, pokeapitest.Stats{HP: 45, Attack: 30, Defense: 35, SpecialAttack: 20, SpecialDefense: 20, Speed: 45}, "bug")
+	cfg := repltest.NewConfig(pokeapitest.NewFake().AddPokemon(caterpie), 1)
+	giveToParty(cfg, pokeapi.UserPokemon{PokemonData: caterpie, UID: 1, Level: 3})
+
+	transcript := repltest.Run(t, cfg, "inspect caterpie")
+
+	if !repltest.Contains(transcript, "Pokedex entry unavailable", "Name: caterpie", "Level: 3") {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+}
+
+func TestScenarioCatchUsesSpeciesCaptureRate(t *testing.T) {
+	mewtwo := pokeapi.PokemonSpecies{ID: 150, Name: "mewtwo", CaptureRate: 3, IsLegendary: true}
+	cfg := repltest.NewConfig(newScenarioFake().AddSpecies(mewtwo), 1)
+
+	transcript := repltest.Run(t, cfg,
+		"travel kanto",
+		"travel cerulean-cave",
+		"explore cerulean-cave-area",
+		"catch",
+		"catch mewtwo",
+		"catch mewtwo greatball",
+		"travel viridian-forest",
+		"explore viridian-forest-area",
+		"catch netball",
+	)
+
+	if !repltest.Contains(transcript,
+		"A wild mewtwo (Lvl 70) has appeared!",
+		"Throwing a Poke Ball at the wild mewtwo...",
+		"The wild mewtwo broke free!",
+		"Throwing a Great Ball at the wild mewtwo...",
+		"You left the wild mewtwo behind.",
+		"A wild caterpie (Lvl 4) has appeared!",
+		"Throwing a Net Ball at the wild caterpie...",
+		"...wobble...",
+		"Click!",
+		"caterpie was caught!",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+	if _, caught := owned(cfg, "mewtwo"); caught {
+		t.Errorf("mewtwo has a capture rate of 3; seed 1 shouldn't catch it in three throws")
+	}
+	if got, _ := owned(cfg, "caterpie"); got.Level != 4 {
+		t.Errorf("caterpie should keep the level it was met at, got %d", got.Level)
+	}
+	if cfg.Encounter != nil {
+		t.Errorf("catching the wild Pokemon should end the encounter")
+	}
+	if cfg.Inventory["netball"] != 1 || cfg.Inventory["pokeball"] != 8 || cfg.Inventory["greatball"] != 4 {
+		t.Errorf("unexpected inventory after four throws: %v", cfg.Inventory)
+	}
+}
+
+func TestScenarioWeakenThenCatch(t *testing.T) {
+	// A tough species with a low capture rate that only a weakened throw is likely to catch.
+	fake := newScenarioFake().
+		AddPokemon(pokeapitest.NewPokemon(143, "snorlax", 189, pokeapitest.Stats{HP: 160, Attack: 1, Defense: 65, SpecialAttack: 65, SpecialDefense: 110, Speed: 1}, "normal")).
+		AddSpecies(pokeapi.PokemonSpecies{ID: 143, Name: "snorlax", CaptureRate: 45}).
+		AddLocationArea(pokeapitest.Area("kanto-route-12-area", pokeapitest.Encounter("snorlax", 30, 30)))
+	cfg := repltest.NewConfig(fake, 1)
+	giveToParty(cfg, pokeapi.UserPokemon{PokemonData: mustFetch(t, fake, "mewtwo"), UID: 1, Level: 70})
+
+	transcript := repltest.Run(t, cfg,
+		"travel kanto",
+		"travel kanto-route-12",
+		"explore kanto-route-12-area",
+		"battle mewtwo",
+		"battle mewtwo",
+		"battle mewtwo",
+		"battle mewtwo",
+	)
+	if !repltest.Contains(transcript,
+		"A wild snorlax (Lvl 30) has appeared!",
+		"--- Turn 1: mewtwo vs wild snorlax (Lvl 30) ---",
+		"mewtwo attacks snorlax!",
+		"--- Turn 4: mewtwo vs wild snorlax (Lvl 30) ---",
+		"Keep battling, or try 'catch' now that it's weaker.",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+	if cfg.Encounter == nil {
+		t.Fatalf("the encounter should still be going")
+	}
+	wild := cfg.Encounter.Wild
+	if wild.HP >= wild.MaxHP || wild.Fainted() {
+		t.Fatalf("snorlax should be weakened but standing, HP %d/%d", wild.HP, wild.MaxHP)
+	}
+	if cfg.Encounter.FighterHP[1] < 100 {
+		t.Errorf("snorlax's 1 attack should barely scratch mewtwo, HP left %d", cfg.Encounter.FighterHP[1])
+	}
+
+	hpBeforeThrows := wild.HP
+	cfg.Inventory["ultraball"] = 5
+	caught := false
+	for range 5 {
+		repltest.Run(t, cfg, "catch ultraball")
+		if _, caught = owned(cfg, "snorlax"); caught {
+			break
+		}
+		if cfg.Encounter.Wild.HP != hpBeforeThrows {
+			t.Fatalf("throwing balls shouldn't change the wild Pokemon's HP")
+		}
+	}
+	if !caught {
+		t.Errorf("expected the weakened snorlax (HP %d/%d) to be caught within five Ultra Balls", hpBeforeThrows, wild.MaxHP)
+	}
+}
+
+func TestScenarioDefeatingTheWildPokemonEndsTheEncounter(t *testing.T) {
+	cfg := repltest.NewConfig(newScenarioFake(), 1)
+	giveToParty(cfg, pokeapi.UserPokemon{PokemonData: mustFetch(t, newScenarioFake(), "mewtwo"), UID: 1, Level: 70})
+
+	transcript := repltest.Run(t, cfg,
+		"travel kanto",
+		"travel viridian-forest",
+		"explore nursery-area",
+		"battle mewtwo",
+		"battle mewtwo",
+		"battle mewtwo",
+		"catch caterpie",
+	)
+
+	if !repltest.Contains(transcript,
+		"The wild caterpie fainted!",
+		"mewtwo gained",
+		"there's no wild Pokemon to catch",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+	if cfg.Encounter != nil {
+		t.Errorf("a fainted wild Pokemon should end the encounter")
+	}
+}
+
+func TestScenarioRunAndEncounterErrors(t *testing.T) {
+	cfg := repltest.NewConfig(newScenarioFake(), 1)
+	giveToParty(cfg, pokeapi.UserPokemon{PokemonData: mustFetch(t, newScenarioFake(), "caterpie"), UID: 1, Level: 1})
+
+	transcript := repltest.Run(t, cfg,
+		"catch",
+		"run",
+		"battle caterpie",
+		"travel kanto",
+		"travel cerulean-cave",
+		"explore cerulean-cave-area",
+		"catch pikachu",
+		"battle caterpie",
+		"battle caterpie",
+		"run",
+		"catch",
+	)
+
+	if !repltest.Contains(transcript,
+		"there's no wild Pokemon to catch. Use 'explore <area>' to find one",
+		"there's no wild Pokemon to run from",
+		"there's no wild Pokemon to battle",
+		"A wild mewtwo (Lvl 70) has appeared!",
+		"there's no wild pikachu here, only the wild mewtwo you're facing",
+		"caterpie fainted!",
+		"caterpie has fainted and can't fight the wild mewtwo again",
+		"Got away safely from the wild mewtwo!",
+		"there's no wild Pokemon to catch",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+	if cfg.Inventory["pokeball"] != 10 {
+		t.Errorf("no ball should have been thrown, have %d pokeballs", cfg.Inventory["pokeball"])
+	}
+}
+
+func TestScenarioCatchWithoutSpeciesData(t *testing.T) {
+	fake := pokeapitest.NewFake().
+		AddPokemon(pokeapitest.NewPokemon(10, "caterpie", 39, pokeapitest.Stats{HP: 45}, "bug")).
+		AddLocationArea(pokeapitest.Area("nursery-area", pokeapitest.Encounter("caterpie", 1, 1))).
+		AddRegion(pokeapi.Region{Name: "kanto"}, pokeapitest.Location("pallet-town"), pokeapitest.Location("viridian-forest", "nursery-area"))
+	cfg := repltest.NewConfig(fake, 1)
+
+	transcript := repltest.Run(t, cfg, "travel kanto", "travel viridian-forest", "explore nursery-area", "catch")
+
+	if !repltest.Contains(transcript, "there's no species data for 'caterpie', so it can't be caught") {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+	if cfg.Inventory["pokeball"] != 10 {
+		t.Errorf("no ball should be used when the catch can't be attempted, have %d", cfg.Inventory["pokeball"])
+	}
+}
+
+// mustFetch returns the Pokemon data registered in fake under name.
+func mustFetch(t *testing.T, fake *pokeapitest.Fake, name string) pokeapi.PokemonData {
+	t.Helper()
+	pokemon, err := fake.FetchPokemon(name)
+	if err != nil {
+		t.Fatalf("fake has no %s: %v", name, err)
+	}
+	return pokemon
+}
+
+// giveToParty hands the player an owned Pokemon and puts it in their party.
+func giveToParty(cfg *repl.Config, p pokeapi.UserPokemon) {
+	cfg.Collection[p.UID] = p
+	cfg.Party = append(cfg.Party, p.UID)
+}
+
+// owned finds a Pokemon of the given species in the player's collection.
+func owned(cfg *repl.Config, species string) (pokeapi.UserPokemon, bool) {
+	for _, p := range cfg.Collection {
+		if p.Name == species {
+			return p, true
+		}
+	}
+	return pokeapi.UserPokemon{}, false
+}
+
+func TestScenarioCommandErrors(t *testing.T) {
+	cfg := repltest.NewConfig(newScenarioFake(), 1)
+
+	transcript := repltest.Run(t, cfg,
+		"fly",
+		"inspect caterpie",
+		"battle caterpie chansey",
+		"explore nowhere-area",
+	)
+
+	if !repltest.Contains(transcript,
+		"Unknown command: fly",
+		"you have not caught caterpie",
+		"you have not caught 'caterpie' to battle with",
+		"you haven't traveled to a location yet",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+}
+
+func TestScenarioAPIErrorsShowTargetedMessages(t *testing.T) {
+	fake := newScenarioFake().
+		FailWith("pikachu", fmt.Errorf("request for pokemon 'pikachu' failed: %w", &pokeapi.HTTPError{Status: http.StatusTooManyRequests, URL: pokeapi.BaseURL + "/pokemon/pikachu"})).
+		FailWith("kanto-route-1-area", fmt.Errorf("request for location area 'kanto-route-1-area' failed: %w: dial tcp: connection refused", pokeapi.ErrNetwork)).
+		AddLocationArea(pokeapitest.Area("power-plant-area", pokeapitest.Encounter("pikachu", 20, 25))).
+		FailWith("ditto", fmt.Errorf("request for pokemon 'ditto' failed: %w", &pokeapi.HTTPError{Status: http.StatusBadGateway, URL: pokeapi.BaseURL + "/pokemon/ditto"}))
+	cfg := repltest.NewConfig(fake, 1)
+
+	transcript := repltest.Run(t, cfg,
+		"travel kanto",
+		"travel power-plant",
+		"explore power-plant-area",
+		"explore kanto-route-1-area",
+		"explore nowhere-area",
+		"travel viridian-forest",
+		"explore nursery-area",
+		"catch caterpie quickball",
+		"battle caterpie ditto",
+		"battle caterpie missingno",
+	)
+
+	if !repltest.Contains(transcript,
+		"PokeAPI is rate limiting requests right now",
+		"could not reach PokeAPI. Check your internet connection",
+		"there's no location area called 'nowhere-area'. Run 'areas' to list the areas here",
+		"caterpie was caught!",
+		"PokeAPI is having trouble (status 502)",
+		"there's no Pokemon called 'missingno' to battle",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+}
+
+func TestScenarioDebugToggle(t *testing.T) {
+	cfg := repltest.NewConfig(newScenarioFake(), 1)
+
+	transcript := repltest.Run(t, cfg, "debug on")
+	if !repltest.Contains(transcript, "logging is not available") {
+		t.Fatalf("expected debug to fail without a log level, got:\n%s", transcript)
+	}
+
+	cfg.LogLevel = new(slog.LevelVar)
+	cfg.LogLevel.Set(logging.LevelOff)
+
+	transcript = repltest.Run(t, cfg, "debug", "debug on", "debug")
+	if !repltest.Contains(transcript, "Debug logging is off", "Debug logging enabled", "Debug logging is on") {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+	if cfg.LogLevel.Level() != slog.LevelDebug {
+		t.Errorf("level after 'debug on' = %v; want DEBUG", cfg.LogLevel.Level())
+	}
+
+	transcript = repltest.Run(t, cfg, "debug off", "debug loud")
+	if !repltest.Contains(transcript, "Debug logging disabled", "usage: debug <on|off>") {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+	if cfg.LogLevel.Level() != logging.LevelOff {
+		t.Errorf("level after 'debug off' = %v; want LevelOff", cfg.LogLevel.Level())
+	}
+}
+
+func TestScenarioTravelThroughARegion(t *testing.T) {
+	cfg := repltest.NewConfig(newScenarioFake(), 1)
+
+	transcript := repltest.Run(t, cfg,
+		"explore nursery-area",
+		"travel kanto",
+		"regions",
+		"travel 1",
+		"areas",
+		"explore 2",
+		"explore cerulean-cave-area",
+	)
+
+	if !repltest.Contains(transcript,
+		"you haven't traveled to a location yet",
+		"Welcome to kanto!",
+		"1: viridian-forest",
+		"- kanto (you are here)",
+		"You arrived at viridian-forest in kanto.",
+		"Areas of viridian-forest:",
+		"2: viridian-forest-area",
+		"A wild caterpie",
+		"cerulean-cave-area is in cerulean-cave, but you're at viridian-forest",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+}
+
+func TestScenarioMoveAlongPathsAndTravelRoutes(t *testing.T) {
+	fake := pokeapitest.NewFake().AddRegion(pokeapi.Region{Name: "kanto"},
+		pokeapitest.Location("pallet-town"),
+		pokeapitest.Location("kanto-route-1", "kanto-route-1-area"),
+		pokeapitest.Location("viridian-city"),
+		pokeapitest.Location("kanto-route-2", "kanto-route-2-south-towards-viridian-city"),
+		pokeapitest.Location("viridian-forest", "viridian-forest-area"),
+		pokeapitest.Location("pewter-city"),
+	)
+	cfg := repltest.NewConfig(fake, 1)
+
+	transcript := repltest.Run(t, cfg,
+		"where",
+		"travel kanto",
+		"where",
+		"move west",
+		"move n",
+		"move viridian-city",
+		"move pewter-city",
+		"travel pewter-city",
+		"travel pewter-city",
+		"move s",
+	)
+
+	if !repltest.Contains(transcript,
+		"you haven't traveled to a location yet",
+		"Welcome to kanto!",
+		"You arrived at pallet-town in kanto.",
+		"You are at pallet-town in kanto.",
+		"north -> kanto-route-1",
+		"there's no path west of pallet-town",
+		"You arrived at kanto-route-1 in kanto.",
+		"south -> pallet-town",
+		"You arrived at viridian-city in kanto.",
+		"pewter-city isn't next to viridian-city",
+		"Route (3 steps): viridian-city -> kanto-route-2 -> viridian-forest -> pewter-city",
+		"You arrived at pewter-city in kanto.",
+		"you're already at pewter-city",
+		"You arrived at viridian-forest in kanto.",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+	if cfg.CurrentLocation != "viridian-forest" {
+		t.Errorf("expected to end up in viridian-forest, at %q", cfg.CurrentLocation)
+	}
+}
+
+func TestScenarioMoveOffTheMap(t *testing.T) {
+	// Johto has no map: its locations are reached straight from the region.
+	fake := newScenarioFake().AddRegion(pokeapi.Region{Name: "johto"}, pokeapitest.Location("ruins-of-alph"), pokeapitest.Location("johto-route-29"))
+	cfg := repltest.NewConfig(fake, 1)
+
+	transcript := repltest.Run(t, cfg, "travel johto", "where", "travel johto-route-29", "move north", "where", "travel 1")
+	if !repltest.Contains(transcript,
+		"Welcome to johto!",
+		"You're in johto, but not at any location yet.",
+		"You arrived at johto-route-29 in johto.",
+		"there's no map of the paths around johto-route-29",
+		"You are at johto-route-29 in johto.",
+		"There's no map of the paths around here",
+		"You arrived at ruins-of-alph in johto.",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+}
+
+func TestScenarioTravelKeepsToTheRegionMap(t *testing.T) {
+	fake := pokeapitest.NewFake().
+		AddRegion(pokeapi.Region{Name: "kanto"}, pokeapitest.Location("pallet-town"), pokeapitest.Location("safari-zone-gate"), pokeapitest.Location("viridian-forest")).
+		AddRegion(pokeapi.Region{Name: "sinnoh"}, pokeapitest.Location("twinleaf-town"), pokeapitest.Location("eterna-city"))
+	cfg := repltest.NewConfig(fake, 1)
+
+	transcript := repltest.Run(t, cfg,
+		"travel viridian-forest",
+		"travel kanto",
+		"travel safari-zone-gate",
+		"travel eterna-city",
+		"travel sinnoh",
+		"travel eterna-city",
+	)
+
+	if !repltest.Contains(transcript,
+		"viridian-forest is in kanto. Run 'travel kanto' to go there first",
+		"You arrived at pallet-town in kanto.",
+		"safari-zone-gate isn't on the map of kanto. Run 'travel kanto' to list the locations you can go to",
+		"eterna-city is in sinnoh. Run 'travel sinnoh' to go there first",
+		"Welcome to sinnoh!",
+		"You arrived at twinleaf-town in sinnoh.",
+		"Route (",
+		"You arrived at eterna-city in sinnoh.",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+	if strings.Contains(transcript, ": safari-zone-gate") {
+		t.Errorf("locations off the map of kanto should not be listed:\n%s", transcript)
+	}
+}
+
+func TestScenarioSimulatedClock(t *testing.T) {
+	cfg := repltest.NewConfig(newScenarioFake(), 1)
+
+	transcript := repltest.Run(t, cfg,
+		"time",
+		"travel kanto",
+		"time set 19:50",
+		"travel viridian-forest",
+		"explore nursery-area",
+		"time",
+		"time set 25:00",
+		"time wall",
+	)
+
+	if !repltest.Contains(transcript,
+		"The clock follows your computer's clock.",
+		"It's 19:50 (day).",
+		"The clock is simulated: it moves 10 minutes with every action.",
+		"It's 20:10 (night).",
+		"invalid time: 25:00",
+		"The clock follows your computer's clock.",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+	if cfg.Clock.Simulated() {
+		t.Errorf("expected 'time wall' to switch back to the wall clock")
+	}
+}
+
+func TestScenarioStopAnEvolutionAndHoldItems(t *testing.T) {
+	cfg := repltest.NewConfig(newScenarioFake(), 1)
+
+	transcript := repltest.Run(t, cfg,
+		"travel kanto",
+		"travel viridian-forest",
+		"explore nursery-area",
+		"catch caterpie quickball",
+		"give everstone caterpie",
+		"inspect caterpie",
+		"take caterpie",
+		"take caterpie",
+		"use fire-stone caterpie",
+		"battle caterpie chansey",
+		"battle caterpie chansey",
+		"battle caterpie chansey",
+		"battle caterpie chansey",
+		"battle caterpie chansey",
+		"battle caterpie chansey",
+		"battle caterpie chansey",
+		"maybe",
+		"n",
+		"party",
+	)
+
+	if !repltest.Contains(transcript,
+		"caterpie is now holding the everstone.",
+		"Held item: everstone",
+		"You took the everstone from caterpie.",
+		"caterpie isn't holding anything",
+		"the fire-stone won't have any effect on caterpie",
+		"What? caterpie is evolving by level-up!",
+		"Let caterpie evolve? (Y/n) > maybe",
+		"Please answer 'y' or 'n'.",
+		"Let caterpie evolve? (Y/n) > n",
+		"Huh? caterpie stopped evolving!",
+		"Slot 1: #1 caterpie (Lvl 7)",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+	if cfg.Inventory["everstone"] != 1 || cfg.Inventory["fire-stone"] != 1 {
+		t.Errorf("items should be back in the bag, inventory = %v", cfg.Inventory)
+	}
+}
+
+func TestScenarioEvolutionsTree(t *testing.T) {
+	fake := newScenarioFake()
+	fake.AddSpecies(pokeapi.PokemonSpecies{Name: "mewtwo"})
+	cfg := repltest.NewConfig(fake, 1)
+
+	transcript := repltest.Run(t, cfg,
+		"travel kanto",
+		"travel viridian-forest",
+		"explore nursery-area",
+		"catch caterpie quickball",
+		"evolutions metapod",
+		"evolutions mewtwo",
+		"evolutions missingno",
+	)
+
+	if !repltest.Contains(transcript,
+		"Evolution chain of metapod:",
+		"caterpie *\n",
+		"`-- [Lv 7] -> metapod\n",
+		"    `-- [Lv 10] -> butterfree\n",
+		"(* caught)",
+		"mewtwo doesn't evolve.",
+		"there's no Pokemon species called 'missingno'",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+}
+
+func TestScenarioEvolutionsTreeMarksReleasedSpeciesCaught(t *testing.T) {
+	fake := newScenarioFake()
+	cfg := repltest.NewConfig(fake, 1)
+	giveToParty(cfg, pokeapi.UserPokemon{PokemonData: mustFetch(t, fake, "mewtwo"), UID: 1, Level: 70})
+
+	transcript := repltest.Run(t, cfg,
+		"travel kanto",
+		"travel viridian-forest",
+		"explore nursery-area",
+		"catch caterpie quickball",
+		"release caterpie",
+		"y",
+		"evolutions caterpie",
+	)
+
+	if _, stillOwned := owned(cfg, "caterpie"); stillOwned {
+		t.Fatalf("caterpie should have been released:\n%s", transcript)
+	}
+	if !repltest.Contains(transcript,
+		"Evolution chain of caterpie:",
+		"caterpie *\n",
+		"(* caught)",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+}
+
+func TestScenarioEvolutionsTreeBranches(t *testing.T) {
+	stats := pokeapitest.Stats{HP: 60, Attack: 60, Defense: 60, SpecialAttack: 60, SpecialDefense: 60, Speed: 60}
+	fake := pokeapitest.NewFake().
+		AddPokemon(
+			pokeapitest.NewPokemon(236, "tyrogue", 42, stats, "fighting"),
+			pokeapitest.NewPokemon(106, "hitmonlee", 159, stats, "fighting"),
+			pokeapitest.NewPokemon(107, "hitmonchan", 159, stats, "fighting"),
+		).
+		AddEvolutionChain(pokeapitest.ChainURL(47), pokeapi.EvolutionChainResponse{
+			ID: 47,
+			Chain: pokeapitest.Link("tyrogue", nil,
+				pokeapitest.Link("hitmonlee", relativeStats(20, 1)),
+				pokeapitest.Link("hitmonchan", relativeStats(20, -1)),
+			),
+		})
+	cfg := repltest.NewConfig(fake, 1)
+
+	transcript := repltest.Run(t, cfg, "evolutions hitmonchan")
+
+	if !repltest.Contains(transcript,
+		"tyrogue\n",
+		"|-- [Lv 20, Attack > Defense] -> hitmonlee\n",
+		"`-- [Lv 20, Attack < Defense] -> hitmonchan\n",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+}
+
+func TestScenarioTradeEvolutions(t *testing.T) {
+	stats := pokeapitest.Stats{HP: 50, Attack: 50, Defense: 50, SpecialAttack: 50, SpecialDefense: 50, Speed: 50}
+	fake := pokeapitest.NewFake().
+		AddPokemon(
+			pokeapitest.NewPokemon(64, "kadabra", 140, stats, "psychic"),
+			pokeapitest.NewPokemon(65, "alakazam", 250, stats, "psychic"),
+			pokeapitest.NewPokemon(95, "onix", 77, stats, "rock", "ground"),
+			pokeapitest.NewPokemon(208, "steelix", 179, stats, "steel", "ground"),
+			pokeapitest.NewPokemon(588, "karrablast", 63, stats, "bug"),
+			pokeapitest.NewPokemon(589, "escavalier", 173, stats, "bug", "steel"),
+		).
+		AddEvolutionChain(pokeapitest.ChainURL(26), pokeapi.EvolutionChainResponse{
+			ID:    26,
+			Chain: pokeapitest.Link("abra", nil, pokeapitest.Link("kadabra", pokeapitest.LevelUp(16), pokeapitest.Link("alakazam", pokeapitest.Trade("", "")))),
+		}).
+		AddEvolutionChain(pokeapitest.ChainURL(46), pokeapi.EvolutionChainResponse{
+			ID:    46,
+			Chain: pokeapitest.Link("onix", nil, pokeapitest.Link("steelix", pokeapitest.Trade("metal-coat", ""))),
+		}).
+		AddEvolutionChain(pokeapitest.ChainURL(306), pokeapi.EvolutionChainResponse{
+			ID:    306,
+			Chain: pokeapitest.Link("karrablast", nil, pokeapitest.Link("escavalier", pokeapitest.Trade("", "shelmet"))),
+		}).
+		AddEvolutionChain(pokeapitest.ChainURL(307), pokeapi.EvolutionChainResponse{
+			ID:    307,
+			Chain: pokeapitest.Link("shelmet", nil, pokeapitest.Link("accelgor", pokeapitest.Trade("", "karrablast"))),
+		})
+	cfg := repltest.NewConfig(fake, 1)
+	giveToParty(cfg, pokeapi.UserPokemon{PokemonData: mustFetch(t, fake, "kadabra"), UID: 1, Level: 20})
+	giveToParty(cfg, pokeapi.UserPokemon{PokemonData: mustFetch(t, fake, "onix"), UID: 2, Level: 20})
+	giveToParty(cfg, pokeapi.UserPokemon{PokemonData: mustFetch(t, fake, "karrablast"), UID: 3, Level: 20})
+	cfg.Inventory["metal-coat"] = 1
+
+	transcript := repltest.Run(t, cfg,
+		"evolutions onix",
+		"trade onix",
+		"give metal-coat onix",
+		"trade onix",
+		"give everstone kadabra",
+		"trade kadabra",
+		"take kadabra",
+		"trade kadabra",
+		"trade karrablast",
+		"trade karrablast for missingno",
+		"trade karrablast for shelmet",
+		"trade machop",
+		"trade",
+	)
+
+	if !repltest.Contains(transcript,
+		"`-- [trade holding metal-coat] -> steelix\n",
+		"You sent onix to your link partner for a Pokemon, and they traded it back.",
+		"onix came back just as it was.",
+		"Congratulations! Your onix evolved into steelix by trade!",
+		"kadabra could evolve into alakazam, but its Everstone keeps it from evolving.",
+		"Congratulations! Your kadabra evolved into alakazam by trade!",
+		"karrablast came back just as it was.",
+		"there's no Pokemon species called 'missingno' to trade for",
+		"You sent karrablast to your link partner for their shelmet, and they traded it back.",
+		"Congratulations! Your karrablast evolved into escavalier by trade!",
+		"you have not caught 'machop' to trade",
+		"usage: trade <pokemon_name> [for <species>]",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+	if steelix := cfg.Collection[2]; steelix.Name != "steelix" || steelix.HeldItem != "" {
+		t.Errorf("#2 = %s holding %q; want a steelix that used up its metal-coat", steelix.Name, steelix.HeldItem)
+	}
+	if cfg.Collection[1].Name != "alakazam" || cfg.Collection[3].Name != "escavalier" {
+		t.Errorf("#1 = %s, #3 = %s; want alakazam and escavalier", cfg.Collection[1].Name, cfg.Collection[3].Name)
+	}
+	if !cfg.Dex.Seen["shelmet"] || !cfg.Dex.Caught["escavalier"] {
+		t.Errorf("Pokedex = %+v; want the partner's shelmet seen and escavalier caught", cfg.Dex)
+	}
+}
+
+func relativeStats(minLevel, relative int) []pokeapi.EvolutionDetail {
+	details := pokeapitest.LevelUp(minLevel)
+	details[0].RelativePhysicalStats = &relative
+	return details
+}
+
+func TestScenarioHappiness(t *testing.T) {
+	cfg := repltest.NewConfig(newScenarioFake(), 1)
+
+	// caterpie starts at the default happiness of 70, walking the 3 steps back to
+	// kanto-route-1 adds 3 and the berry 10.
+	transcript := repltest.Run(t, cfg,
+		"travel kanto",
+		"travel viridian-forest",
+		"explore nursery-area",
+		"catch caterpie quickball",
+		"travel kanto-route-1",
+		"use pomeg-berry caterpie",
+		"inspect caterpie",
+	)
+
+	if !repltest.Contains(transcript,
+		"caterpie was caught!",
+		"You used the pomeg-berry on caterpie. It's not used to you yet.",
+		"Happiness: 83/255",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+}
+
+func TestScenarioOwnSeveralOfASpecies(t *testing.T) {
+	cfg := repltest.NewConfig(newScenarioFake(), 1)
+
+	transcript := repltest.Run(t, cfg,
+		"travel kanto",
+		"travel viridian-forest",
+		"explore nursery-area",
+		"catch caterpie quickball",
+		"explore nursery-area",
+		"catch caterpie quickball",
+		"inspect caterpie",
+		"nickname #2 wormy",
+		"give everstone wormy",
+		"pokemon",
+		"party",
+	)
+
+	if !repltest.Contains(transcript,
+		"caterpie has been added to your party as #1!",
+		"caterpie has been added to your party as #2!",
+		"you have 2 Pokemon called caterpie: #1 caterpie (Lvl 1), #2 caterpie (Lvl 1). Use the ID to pick one, e.g. '#1'",
+		"caterpie is now called wormy.",
+		"wormy is now holding the everstone.",
+		"#1  caterpie  caterpie    1  bug",
+		"#2  wormy     caterpie    1  bug",
+		"Slot 2: #2 wormy (caterpie) (Lvl 1)",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+	if len(cfg.Collection) != 2 || cfg.Collection[2].HeldItem != "everstone" || cfg.Collection[1].HeldItem != "" {
+		t.Errorf("expected two caterpie with only #2 holding the everstone, got %v", cfg.Collection)
+	}
+}
+
+func newDexFake() *pokeapitest.Fake {
+	national := pokeapitest.Pokedex(pokeapi.NationalPokedex, "", 10, "caterpie", "metapod", "butterfree", "weedle")
+	national.PokemonEntries = append(national.PokemonEntries, pokeapi.PokedexEntry{EntryNumber: 113, PokemonSpecies: pokeapi.NamedAPIResource{Name: "chansey"}})
+	return newScenarioFake().
+		AddRegion(pokeapi.Region{Name: "johto"}).
+		AddPokedex(
+			national,
+			pokeapitest.Pokedex("kanto", "kanto", 23, "caterpie", "metapod", "butterfree", "chansey"),
+			pokeapitest.Pokedex("original-johto", "johto", 1, "chikorita", "bayleef"),
+		).
+		AddGeneration(
+			pokeapitest.Generation("generation-i", "kanto", "caterpie", "metapod", "butterfree", "weedle", "chansey"),
+			pokeapitest.Generation("generation-ii", "johto", "chikorita", "bayleef"),
+		)
+}
+
+func TestScenarioPokedexSeenAndCaught(t *testing.T) {
+	cfg := repltest.NewConfig(newDexFake(), 1)
+
+	transcript := repltest.Run(t, cfg,
+		"pokedex",
+		"travel kanto",
+		"travel viridian-forest",
+		"explore nursery-area",
+		"catch caterpie quickball",
+		"battle caterpie chansey",
+		"pokedex",
+		"pokedex kanto",
+		"pokedex johto",
+		"pokedex hoenn",
+		"pokedex stats",
+	)
+
+	if !repltest.Contains(transcript,
+		"Your Pokedex is empty. Go explore and find some Pokemon!",
+		"caterpie's data was added to the Pokedex.",
+		"National Pokedex:\n  #010 caterpie       caught\n  #113 chansey        seen\nSeen: 2  Caught: 1 of 5",
+		"kanto Pokedex:\n  #023 caterpie       caught\n  #026 chansey        seen\nSeen: 2  Caught: 1 of 4",
+		"original-johto Pokedex:\n  You haven't seen any Pokemon of this Pokedex yet.\nSeen: 0  Caught: 0 of 2",
+		"there's no Pokedex or region called 'hoenn'",
+		"Seen 2 species, caught 1.",
+		"By region:\n  kanto               1/4     25.0%\n  johto               0/2      0.0%",
+		"By generation:\n  generation-i        1/5     20.0%\n  generation-ii       0/2      0.0%",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+}
+
+func TestScenarioRegionsPastTheFirstPage(t *testing.T) {
+	// More regions than fit on one page of PokeAPI's region list, with the one that
+	// has a Pokedex last.
+	fake := pokeapitest.NewFake()
+	for i := range pokeapi.DefaultPageLimit {
+		fake.AddRegion(pokeapi.Region{Name: fmt.Sprintf("region-%d", i+1)})
+	}
+	fake.AddRegion(pokeapi.Region{Name: "johto", Pokedexes: []pokeapi.NamedAPIResource{{Name: "original-johto"}}}).
+		AddPokedex(pokeapitest.Pokedex("original-johto", "johto", 1, "chikorita", "bayleef"))
+	cfg := repltest.NewConfig(fake, 1)
+
+	transcript := repltest.Run(t, cfg, "regions", "pokedex stats")
+	if !repltest.Contains(transcript,
+		"- region-20",
+		"- johto",
+		"By region:\n  johto               0/2      0.0%",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+}
+
+func TestScenarioPCBoxes(t *testing.T) {
+	cfg := repltest.NewConfig(newScenarioFake(), 1)
+
+	transcript := repltest.Run(t, cfg,
+		"travel kanto",
+		"travel viridian-forest",
+		"explore nursery-area",
+		"catch caterpie quickball",
+		"explore nursery-area",
+		"catch caterpie quickball",
+		"deposit #2",
+		"deposit #1",
+		"battle #2 chansey",
+		"explore nursery-area",
+		"battle #2",
+		"box rename 1 bugs",
+		"box list",
+		"box bugs",
+		"withdraw #2",
+		"party",
+	)
+
+	if !repltest.Contains(transcript,
+		"caterpie was stored in Box 1.",
+		"caterpie is the last Pokemon in your party: you can't deposit it",
+		"#2 caterpie is in Box 1, not your party. Take it out with 'withdraw #2' to battle with it",
+		"A wild caterpie (Lvl 1) has appeared!",
+		"Box 1 is now called bugs.",
+		"1. bugs              1/30",
+		"8. Box 8             0/30",
+		"bugs (1/30):",
+		"#2  caterpie  caterpie    1  bug",
+		"You took caterpie out of bugs. It's in slot 2 of your party.",
+		"Slot 2: #2 caterpie (Lvl 1)",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+	if n := strings.Count(transcript, "Take it out with 'withdraw #2' to battle with it"); n != 2 {
+		t.Errorf("a boxed Pokemon should be refused in practice and wild battles, got %d refusals:\n%s", n, transcript)
+	}
+}
+
+func TestScenarioPartyLeadAndRelease(t *testing.T) {
+	cfg := repltest.NewConfig(newScenarioFake(), 1)
+
+	transcript := repltest.Run(t, cfg,
+		"travel kanto",
+		"travel viridian-forest",
+		"explore nursery-area",
+		"catch caterpie quickball",
+		"explore nursery-area",
+		"catch caterpie quickball",
+		"nickname #2 wormy",
+		"party lead 2",
+		"party",
+		"explore nursery-area",
+		"battle",
+		"release #1",
+		"y",
+		"party",
+	)
+
+	if !repltest.Contains(transcript,
+		"wormy leads your party.",
+		"Slot 1: #2 wormy (caterpie) (Lvl 1)",
+		"Lead: fights when 'battle' names no Pokemon",
+		"--- Turn 1: wormy vs wild caterpie (Lvl 1) ---",
+		"Release #1 caterpie (Lvl 1)? You won't get it back. (y/N) >",
+		"caterpie was released. Bye-bye, caterpie!",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+	if len(cfg.Collection) != 1 || len(cfg.Party) != 1 || cfg.Party[0] != 2 {
+		t.Errorf("only wormy should be left, collection = %v, party = %v", cfg.Collection, cfg.Party)
+	}
+}
+
+func TestScenarioBattleCanBurnTheWildPokemon(t *testing.T) {
+	// A feeble fire type whose attacks barely scratch, so the wild caterpie has time to
+	// catch a burn.
+	fake := newScenarioFake().AddPokemon(pokeapitest.NewPokemon(218, "slugma", 50, pokeapitest.Stats{HP: 200, Attack: 1, Defense: 200, SpecialAttack: 70, SpecialDefense: 40, Speed: 50}, "fire"))
+	cfg := repltest.NewConfig(fake, 1)
+	giveToParty(cfg, pokeapi.UserPokemon{PokemonData: mustFetch(t, fake, "slugma"), UID: 1, Level: 20})
+
+	transcript := repltest.Run(t, cfg, "travel kanto", "travel viridian-forest", "explore nursery-area")
+	for range 40 {
+		if cfg.Encounter == nil || cfg.Encounter.Wild.Status != "" {
+			break
+		}
+		transcript += repltest.Run(t, cfg, "battle")
+	}
+	if cfg.Encounter == nil || cfg.Encounter.Wild.Status != battle.StatusBurn {
+		t.Fatalf("expected slugma to burn the wild caterpie:\n%s", transcript)
+	}
+	if !repltest.Contains(transcript,
+		"caterpie was burned!",
+		"Wild caterpie HP:",
+		"[burn]",
+	) {
+		t.Fatalf("unexpected transcript:\n%s", transcript)
+	}
+}
@@ -0,0 +1,295 @@
+package repl
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+	"github.com/voidarchive/pokedex/internal/shared/constants"
+)
+
+// maxBoxNameLength is how long a box name can be.
+const maxBoxNameLength = 16
+
+// Box is a PC box: a name and the UIDs of the Pokemon stored in it, in the order they
+// were put in. Every owned Pokemon is either in the party or in exactly one box.
+type Box struct {
+	Name    string `json:"name"`
+	Pokemon []int  `json:"pokemon"`
+}
+
+// newBoxes returns the empty boxes of a new game, "Box 1" to "Box 8".
+func newBoxes() []Box {
+	boxes := make([]Box, NumBoxes)
+	for i := range boxes {
+		boxes[i] = Box{Name: fmt.Sprintf("Box %d", i+1), Pokemon: []int{}}
+	}
+	return boxes
+}
+
+// partySlot returns the index of the Pokemon in the party, or -1 if it isn't in it.
+func partySlot(cfg *Config, uid int) int {
+	return slices.Index(cfg.Party, uid)
+}
+
+// boxOf returns the index of the box the Pokemon is stored in, or -1.
+func boxOf(cfg *Config, uid int) int {
+	return slices.IndexFunc(cfg.Boxes, func(b Box) bool { return slices.Contains(b.Pokemon, uid) })
+}
+
+// whereIs names where an owned Pokemon is kept: "your party" or the name of its box.
+func whereIs(cfg *Config, uid int) string {
+	if partySlot(cfg, uid) >= 0 {
+		return "your party"
+	}
+	if box := boxOf(cfg, uid); box >= 0 {
+		return cfg.Boxes[box].Name
+	}
+	return "your PC"
+}
+
+// freeBox returns the index of the first box with room, or -1 if the PC is full.
+func freeBox(cfg *Config) int {
+	return slices.IndexFunc(cfg.Boxes, func(b Box) bool { return len(b.Pokemon) < BoxCapacity })
+}
+
+// roomForPokemon reports whether a newly caught Pokemon fits in the party or the PC.
+func roomForPokemon(cfg *Config) bool {
+	return len(cfg.Party) < MaxPartySize || freeBox(cfg) >= 0
+}
+
+// storeInPC puts an owned Pokemon in the first box with room and returns its index.
+// Should the PC be full, which only repairing a loaded save can run into, a box is
+// added for it rather than overfilling one or losing the Pokemon.
+func storeInPC(cfg *Config, uid int) int {
+	box := freeBox(cfg)
+	if box < 0 {
+		box = addBox(cfg)
+	}
+	cfg.Boxes[box].Pokemon = append(cfg.Boxes[box].Pokemon, uid)
+	return box
+}
+
+// addBox adds an empty box after the last one, named by its number, and returns its index.
+func addBox(cfg *Config) int {
+	cfg.Boxes = append(cfg.Boxes, Box{Name: fmt.Sprintf("Box %d", len(cfg.Boxes)+1), Pokemon: []int{}})
+	return len(cfg.Boxes) - 1
+}
+
+// takeFromPC removes the Pokemon from the box it is in, if any.
+func takeFromPC(cfg *Config, uid int) {
+	if box := boxOf(cfg, uid); box >= 0 {
+		cfg.Boxes[box].Pokemon = slices.DeleteFunc(cfg.Boxes[box].Pokemon, func(id int) bool { return id == uid })
+	}
+}
+
+// findBox finds a box by its number, e.g. "2", or its name.
+func findBox(cfg *Config, ref string) (int, error) {
+	if number, err := strconv.Atoi(ref); err == nil {
+		if number < 1 || number > len(cfg.Boxes) {
+			return -1, fmt.Errorf("%sthere's no box %s%d%s. Boxes are numbered 1 to %d%s", constants.ColorYellow, constants.ColorBrightRed, number, constants.ColorYellow, len(cfg.Boxes), constants.ColorReset)
+		}
+		return number - 1, nil
+	}
+	if i := slices.IndexFunc(cfg.Boxes, func(b Box) bool { return strings.EqualFold(b.Name, ref) }); i >= 0 {
+		return i, nil
+	}
+	return -1, fmt.Errorf("%sthere's no box called '%s%s%s'. Type 'box list' to see your boxes%s", constants.ColorYellow, constants.ColorBrightRed, ref, constants.ColorYellow, constants.ColorReset)
+}
+
+// repairStorage makes sure every owned Pokemon is in exactly one place, the party or a
+// box, and that the party holds at most MaxPartySize Pokemon. Saves from before PC
+// boxes existed get the Pokemon outside their party boxed this way.
+func repairStorage(cfg *Config) {
+	for len(cfg.Boxes) < NumBoxes {
+		addBox(cfg)
+	}
+
+	placed := make(map[int]bool)
+	party := []int{}
+	for _, uid := range cfg.Party {
+		if _, owned := cfg.Collection[uid]; owned && !placed[uid] && len(party) < MaxPartySize {
+			party = append(party, uid)
+			placed[uid] = true
+		}
+	}
+	cfg.Party = party
+	for i, box := range cfg.Boxes {
+		stored := []int{}
+		for _, uid := range box.Pokemon {
+			if _, owned := cfg.Collection[uid]; owned && !placed[uid] {
+				stored = append(stored, uid)
+				placed[uid] = true
+			}
+		}
+		cfg.Boxes[i].Pokemon = stored
+	}
+	for _, p := range ownedPokemon(cfg) {
+		if !placed[p.UID] {
+			storeInPC(cfg, p.UID)
+		}
+	}
+}
+
+// commandDeposit moves a party Pokemon into a PC box: the one given, or the first with
+// room. The party can't be left empty.
+func commandDeposit(cfg *Config, args ...string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("%susage: deposit <pokemon> [box]%s", constants.ColorYellow, constants.ColorReset)
+	}
+	pokemon, err := resolvePokemon(cfg, args[0])
+	if err != nil {
+		return err
+	}
+	if partySlot(cfg, pokemon.UID) < 0 {
+		return fmt.Errorf("%s%s is already in %s%s", constants.ColorYellow, pokemon.DisplayName(), whereIs(cfg, pokemon.UID), constants.ColorReset)
+	}
+
+	box := freeBox(cfg)
+	if len(args) > 1 {
+		if box, err = findBox(cfg, strings.Join(args[1:], " ")); err != nil {
+			return err
+		}
+	}
+	return moveToBox(cfg, pokemon, box)
+}
+
+// commandWithdraw moves a Pokemon from its PC box into the party, if there's room.
+func commandWithdraw(cfg *Config, args ...string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%susage: withdraw <pokemon>%s", constants.ColorYellow, constants.ColorReset)
+	}
+	pokemon, err := resolvePokemon(cfg, args[0])
+	if err != nil {
+		return err
+	}
+	if partySlot(cfg, pokemon.UID) >= 0 {
+		return fmt.Errorf("%s%s is already in your party%s", constants.ColorYellow, pokemon.DisplayName(), constants.ColorReset)
+	}
+	if len(cfg.Party) >= MaxPartySize {
+		return fmt.Errorf("%syour party is full. Deposit a Pokemon first, e.g. 'deposit %s'%s", constants.ColorYellow, pokemonRef(cfg.Collection[cfg.Party[len(cfg.Party)-1]]), constants.ColorReset)
+	}
+
+	from := whereIs(cfg, pokemon.UID)
+	takeFromPC(cfg, pokemon.UID)
+	cfg.Party = append(cfg.Party, pokemon.UID)
+	fmt.Printf("%sYou took %s%s%s out of %s. It's in slot %d of your party.%s\n", constants.ColorCyan, constants.ColorYellow, pokemon.DisplayName(), constants.ColorCyan, from, len(cfg.Party), constants.ColorReset)
+	return nil
+}
+
+// moveToBox moves an owned Pokemon, from the party or another box, into box.
+func moveToBox(cfg *Config, pokemon pokeapi.UserPokemon, box int) error {
+	if box < 0 {
+		return fmt.Errorf("%syour PC boxes are all full%s", constants.ColorYellow, constants.ColorReset)
+	}
+	if boxOf(cfg, pokemon.UID) == box {
+		return fmt.Errorf("%s%s is already in %s%s", constants.ColorYellow, pokemon.DisplayName(), cfg.Boxes[box].Name, constants.ColorReset)
+	}
+	if len(cfg.Boxes[box].Pokemon) >= BoxCapacity {
+		return fmt.Errorf("%s%s is full%s", constants.ColorYellow, cfg.Boxes[box].Name, constants.ColorReset)
+	}
+	if partySlot(cfg, pokemon.UID) >= 0 && len(cfg.Party) == 1 {
+		return fmt.Errorf("%s%s is the last Pokemon in your party: you can't deposit it%s", constants.ColorYellow, pokemon.DisplayName(), constants.ColorReset)
+	}
+
+	removeFromParty(cfg, pokemon.UID)
+	takeFromPC(cfg, pokemon.UID)
+	cfg.Boxes[box].Pokemon = append(cfg.Boxes[box].Pokemon, pokemon.UID)
+	fmt.Printf("%s%s%s was stored in %s%s%s.%s\n", constants.ColorYellow, pokemon.DisplayName(), constants.ColorCyan, constants.ColorYellow, cfg.Boxes[box].Name, constants.ColorCyan, constants.ColorReset)
+	return nil
+}
+
+// commandBox manages the PC boxes: 'box list', 'box <box>' to see what's in one,
+// 'box rename <box> <name>' and 'box move <pokemon> <box>'.
+func commandBox(cfg *Config, args ...string) error {
+	usage := fmt.Errorf("%susage: box list | box <box> | box rename <box> <name> | box move <pokemon> <box>%s", constants.ColorYellow, constants.ColorReset)
+	switch {
+	case len(args) == 0 || len(args) == 1 && args[0] == "list":
+		printBoxes(cfg)
+		return nil
+	case args[0] == "rename":
+		if len(args) < 3 {
+			return usage
+		}
+		box, err := findBox(cfg, args[1])
+		if err != nil {
+			return err
+		}
+		return renameBox(cfg, box, strings.Join(args[2:], " "))
+	case args[0] == "move":
+		if len(args) < 3 {
+			return usage
+		}
+		pokemon, err := resolvePokemon(cfg, args[1])
+		if err != nil {
+			return err
+		}
+		box, err := findBox(cfg, strings.Join(args[2:], " "))
+		if err != nil {
+			return err
+		}
+		return moveToBox(cfg, pokemon, box)
+	default:
+		box, err := findBox(cfg, strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+		printBox(cfg, box)
+		return nil
+	}
+}
+
+// boxSubcommands can't be box names, or 'box <name>' would be ambiguous.
+var boxSubcommands = []string{"list", "rename", "move"}
+
+// renameBox gives a box a new name, unique among the boxes.
+func renameBox(cfg *Config, box int, name string) error {
+	if len([]rune(name)) > maxBoxNameLength {
+		return fmt.Errorf("%sa box name can be at most %d characters long%s", constants.ColorYellow, maxBoxNameLength, constants.ColorReset)
+	}
+	if _, err := strconv.Atoi(name); err == nil || slices.Contains(boxSubcommands, name) {
+		return fmt.Errorf("%s'%s%s%s' can't be a box name%s", constants.ColorYellow, constants.ColorBrightRed, name, constants.ColorYellow, constants.ColorReset)
+	}
+	if other, err := findBox(cfg, name); err == nil && other != box {
+		return fmt.Errorf("%sbox %d is already called %s%s", constants.ColorYellow, other+1, cfg.Boxes[other].Name, constants.ColorReset)
+	}
+	old := cfg.Boxes[box].Name
+	cfg.Boxes[box].Name = name
+	fmt.Printf("%s%s%s is now called %s%s%s.%s\n", constants.ColorYellow, old, constants.ColorCyan, constants.ColorBrightYellow, name, constants.ColorCyan, constants.ColorReset)
+	return nil
+}
+
+// printBoxes lists the boxes with how full they are.
+func printBoxes(cfg *Config) {
+	fmt.Printf("\n%sPC boxes:%s\n", constants.ColorBrightCyan, constants.ColorReset)
+	for i, box := range cfg.Boxes {
+		color := constants.ColorWhite
+		if len(box.Pokemon) >= BoxCapacity {
+			color = constants.ColorRed
+		}
+		fmt.Printf("  %s%d. %s%-*s %s%2d/%d%s\n", constants.ColorGray, i+1, constants.ColorWhite, maxBoxNameLength, box.Name, color, len(box.Pokemon), BoxCapacity, constants.ColorReset)
+	}
+	fmt.Printf("\n%sType 'box <number>' to see what's in a box.%s\n", constants.ColorGray, constants.ColorReset)
+}
+
+// printBox shows the Pokemon stored in a box as a table.
+func printBox(cfg *Config, box int) {
+	fmt.Printf("\n%s%s (%d/%d):%s\n", constants.ColorBrightCyan, cfg.Boxes[box].Name, len(cfg.Boxes[box].Pokemon), BoxCapacity, constants.ColorReset)
+	if len(cfg.Boxes[box].Pokemon) == 0 {
+		fmt.Printf("  %sThis box is empty.%s\n\n", constants.ColorGray, constants.ColorReset)
+		return
+	}
+	pokemon := make([]pokeapi.UserPokemon, len(cfg.Boxes[box].Pokemon))
+	for i, uid := range cfg.Boxes[box].Pokemon {
+		pokemon[i] = cfg.Collection[uid]
+	}
+	printPokemonTable(pokemon)
+	fmt.Println()
+}
+
+// pokemonRef is how the player can refer to an owned Pokemon in a command, e.g. "#3".
+func pokemonRef(p pokeapi.UserPokemon) string {
+	return "#" + strconv.Itoa(p.UID)
+}
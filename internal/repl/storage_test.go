@@ -0,0 +1,183 @@
+package repl
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/voidarchive/pokedex/internal/pokeapi"
+)
+
+// fillParty catches pikachu until the party is full.
+func fillParty(t *testing.T, cfg *Config) {
+	t.Helper()
+	data, err := cfg.PokeapiClient.FetchPokemon("pikachu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for len(cfg.Party) < MaxPartySize {
+		addOwnedPokemon(cfg, pokeapi.NewUserPokemon(data, 5))
+	}
+}
+
+func TestCaughtPokemonGoToThePCWhenThePartyIsFull(t *testing.T) {
+	cfg := newCollectionConfig(t)
+	fillParty(t, cfg)
+
+	data, _ := cfg.PokeapiClient.FetchPokemon("eevee")
+	p, inParty := addOwnedPokemon(cfg, pokeapi.NewUserPokemon(data, 5))
+	if inParty || !slices.Equal(cfg.Boxes[0].Pokemon, []int{p.UID}) {
+		t.Fatalf("#%d inParty=%v, Box 1 = %v; want it stored in Box 1", p.UID, inParty, cfg.Boxes[0].Pokemon)
+	}
+
+	// With Box 1 full, the next catch goes to Box 2, and with every box full there's
+	// no room left.
+	for len(cfg.Boxes[0].Pokemon) < BoxCapacity {
+		addOwnedPokemon(cfg, pokeapi.NewUserPokemon(data, 5))
+	}
+	p, _ = addOwnedPokemon(cfg, pokeapi.NewUserPokemon(data, 5))
+	if boxOf(cfg, p.UID) != 1 {
+		t.Errorf("#%d is in box %d; want Box 2", p.UID, boxOf(cfg, p.UID)+1)
+	}
+	for i := range cfg.Boxes {
+		cfg.Boxes[i].Pokemon = make([]int, BoxCapacity)
+	}
+	if roomForPokemon(cfg) {
+		t.Error("roomForPokemon = true with a full party and full boxes")
+	}
+}
+
+func TestDepositAndWithdraw(t *testing.T) {
+	cfg := newCollectionConfig(t)
+
+	if err := commandDeposit(cfg, "#2"); err != nil {
+		t.Fatalf("deposit: %v", err)
+	}
+	if partySlot(cfg, 2) >= 0 || boxOf(cfg, 2) != 0 {
+		t.Errorf("#2 party slot %d, box %d; want it out of the party and in Box 1", partySlot(cfg, 2), boxOf(cfg, 2))
+	}
+	if err := commandDeposit(cfg, "eevee", "3"); err != nil {
+		t.Fatalf("deposit into box 3: %v", err)
+	}
+	if boxOf(cfg, 3) != 2 {
+		t.Errorf("eevee is in box %d; want Box 3", boxOf(cfg, 3)+1)
+	}
+	if err := commandBox(cfg, "move", "#2", "box 3"); err != nil {
+		t.Fatalf("box move: %v", err)
+	}
+	if !slices.Equal(cfg.Boxes[2].Pokemon, []int{3, 2}) || len(cfg.Boxes[0].Pokemon) != 0 {
+		t.Errorf("boxes 1 and 3 = %v and %v; want [] and [3 2]", cfg.Boxes[0].Pokemon, cfg.Boxes[2].Pokemon)
+	}
+
+	if err := commandWithdraw(cfg, "#2"); err != nil {
+		t.Fatalf("withdraw: %v", err)
+	}
+	if partySlot(cfg, 2) != 1 || boxOf(cfg, 2) >= 0 {
+		t.Errorf("#2 party slot %d, box %d; want it back in slot 2 and out of the PC", partySlot(cfg, 2), boxOf(cfg, 2))
+	}
+}
+
+func TestStorageErrors(t *testing.T) {
+	cfg := newCollectionConfig(t)
+	if err := commandDeposit(cfg, "eevee"); err != nil {
+		t.Fatal(err)
+	}
+	if err := commandDeposit(cfg, "#2"); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Boxes[1].Pokemon = make([]int, BoxCapacity)
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr string
+	}{
+		{"deposit the last party member", func() error { return commandDeposit(cfg, "#1") }, "is the last Pokemon in your party"},
+		{"deposit a boxed Pokemon", func() error { return commandDeposit(cfg, "eevee") }, "eevee is already in Box 1"},
+		{"deposit into a full box", func() error { return commandBox(cfg, "move", "eevee", "2") }, "Box 2 is full"},
+		{"move into the same box", func() error { return commandBox(cfg, "move", "eevee", "1") }, "eevee is already in Box 1"},
+		{"withdraw a party Pokemon", func() error { return commandWithdraw(cfg, "#1") }, "pikachu is already in your party"},
+		{"unknown box number", func() error { return commandBox(cfg, "9") }, "Boxes are numbered 1 to 8"},
+		{"unknown box name", func() error { return commandBox(cfg, "attic") }, "there's no box called"},
+		{"rename to a number", func() error { return commandBox(cfg, "rename", "1", "3") }, "can't be a box name"},
+		{"rename to a taken name", func() error { return commandBox(cfg, "rename", "1", "box", "2") }, "box 2 is already called Box 2"},
+		{"rename to a long name", func() error { return commandBox(cfg, "rename", "1", "a-very-long-box-name") }, "at most 16 characters"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.run(); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("error = %v; want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+
+	fillParty(t, cfg)
+	if err := commandWithdraw(cfg, "eevee"); err == nil || !strings.Contains(err.Error(), "your party is full") {
+		t.Errorf("withdraw into a full party: error = %v; want a full party error", err)
+	}
+}
+
+func TestRenameBox(t *testing.T) {
+	cfg := newCollectionConfig(t)
+	if err := commandBox(cfg, "rename", "1", "Electric", "types"); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if err := commandDeposit(cfg, "#2", "electric", "TYPES"); err != nil {
+		t.Fatalf("deposit by box name: %v", err)
+	}
+	if cfg.Boxes[0].Name != "Electric types" || boxOf(cfg, 2) != 0 {
+		t.Errorf("Box 1 = %+v; want it renamed and holding #2", cfg.Boxes[0])
+	}
+}
+
+func TestRepairStorage(t *testing.T) {
+	cfg := newCollectionConfig(t)
+	// A duplicated party member, a boxed party member, a box entry nobody owns and a
+	// Pokemon that is nowhere.
+	cfg.Party = []int{1, 1, 2}
+	cfg.Boxes = cfg.Boxes[:2]
+	cfg.Boxes[1].Pokemon = []int{2, 42}
+
+	repairStorage(cfg)
+	if len(cfg.Boxes) != NumBoxes {
+		t.Errorf("%d boxes; want %d", len(cfg.Boxes), NumBoxes)
+	}
+	if len(cfg.Party) != 2 || partySlot(cfg, 1) != 0 || partySlot(cfg, 2) != 1 {
+		t.Errorf("party = %v; want #1 and #2 once each", cfg.Party)
+	}
+	if len(cfg.Boxes[1].Pokemon) != 0 || !slices.Equal(cfg.Boxes[0].Pokemon, []int{3}) {
+		t.Errorf("boxes 1 and 2 = %v and %v; want [3] and []", cfg.Boxes[0].Pokemon, cfg.Boxes[1].Pokemon)
+	}
+}
+
+func TestRepairStorageAddsABoxWhenThePCIsFull(t *testing.T) {
+	cfg := newCollectionConfig(t)
+	// A save whose party and boxes are full, with #3 left out of all of them.
+	cfg.Party = []int{1, 2}
+	for i := range cfg.Boxes {
+		cfg.Boxes[i].Pokemon = make([]int, BoxCapacity)
+		for j := range cfg.Boxes[i].Pokemon {
+			uid := 100 + i*BoxCapacity + j
+			cfg.Collection[uid] = pokeapi.UserPokemon{PokemonData: pokeapi.PokemonData{Name: "pikachu"}, UID: uid}
+			cfg.Boxes[i].Pokemon[j] = uid
+		}
+	}
+	for len(cfg.Party) < MaxPartySize {
+		uid := cfg.newUID()
+		cfg.Collection[uid] = pokeapi.UserPokemon{PokemonData: pokeapi.PokemonData{Name: "pikachu"}, UID: uid}
+		cfg.Party = append(cfg.Party, uid)
+	}
+
+	repairStorage(cfg)
+	for i, box := range cfg.Boxes {
+		if len(box.Pokemon) > BoxCapacity {
+			t.Errorf("%s holds %d Pokemon; want at most %d", box.Name, len(box.Pokemon), BoxCapacity)
+		}
+		if i == NumBoxes && (box.Name != "Box 9" || !slices.Equal(box.Pokemon, []int{3})) {
+			t.Errorf("added box = %+v; want Box 9 holding #3", box)
+		}
+	}
+	if len(cfg.Boxes) != NumBoxes+1 {
+		t.Errorf("%d boxes; want a box added to the %d full ones", len(cfg.Boxes), NumBoxes)
+	}
+}
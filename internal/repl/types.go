@@ -1,13 +1,23 @@
 package repl
 
 import (
+	"context"
+	"iter"
+	"log/slog"
 	"math/rand"
 
+	"github.com/voidarchive/pokedex/internal/battle"
 	"github.com/voidarchive/pokedex/internal/pokeapi"
 )
 
 const MaxPartySize = 6
 
+// The PC has NumBoxes boxes of BoxCapacity Pokemon each, like in the games.
+const (
+	NumBoxes    = 8
+	BoxCapacity = 30
+)
+
 // UserPokemon has been moved to internal/pokeapi/client.go
 
 // PokeballType defines the properties of a Pokeball.
@@ -15,14 +25,28 @@ type PokeballType struct {
 	Name         string
 	CatchRateMod float64 // e.g., 1.0 for Poke Ball, 1.5 for Great Ball, 2.0 for Ultra Ball
 	Color        string  // ANSI color code for display
+	// Bonus replaces CatchRateMod for balls that depend on the situation, like the Timer Ball.
+	Bonus func(battle.BallContext) float64
+}
+
+// CatchBonus returns the ball multiplier for a throw in the given situation.
+func (b PokeballType) CatchBonus(ctx battle.BallContext) float64 {
+	if b.Bonus != nil {
+		return b.Bonus(ctx)
+	}
+	return b.CatchRateMod
 }
 
 // KnownPokeballs maps ball names to their properties.
 // This can be expanded with more ball types.
 var KnownPokeballs = map[string]PokeballType{
-	"pokeball":  {Name: "Poke Ball", CatchRateMod: 1.0, Color: "\033[0;37m"},  // White
-	"greatball": {Name: "Great Ball", CatchRateMod: 1.5, Color: "\033[0;34m"}, // Blue
-	"ultraball": {Name: "Ultra Ball", CatchRateMod: 2.0, Color: "\033[0;33m"}, // Yellow/Gold
+	"pokeball":  {Name: "Poke Ball", CatchRateMod: 1.0, Color: "\033[0;37m"},             // White
+	"greatball": {Name: "Great Ball", CatchRateMod: 1.5, Color: "\033[0;34m"},            // Blue
+	"ultraball": {Name: "Ultra Ball", CatchRateMod: 2.0, Color: "\033[0;33m"},            // Yellow/Gold
+	"timerball": {Name: "Timer Ball", Bonus: battle.TimerBallBonus, Color: "\033[0;31m"}, // Red
+	"quickball": {Name: "Quick Ball", Bonus: battle.QuickBallBonus, Color: "\033[0;36m"}, // Cyan
+	"netball":   {Name: "Net Ball", Bonus: battle.NetBallBonus, Color: "\033[0;32m"},     // Green
+	"duskball":  {Name: "Dusk Ball", Bonus: battle.DuskBallBonus, Color: "\033[0;90m"},   // Gray
 	// Add Master Ball later if desired: {Name: "Master Ball", CatchRateMod: 255.0, Color: "\033[0;35m"}, // Purple
 }
 
@@ -33,11 +57,38 @@ type Config struct {
 	PrevLocationAreaURL *string
 	PokeapiClient       PokeapiClient
 	Cache               Pokecache
-	Pokedex             map[string]pokeapi.UserPokemon
-	Party               []pokeapi.UserPokemon
-	Inventory           map[string]int // Item name -> count (e.g., "pokeball" -> 10)
+	Collection          map[int]pokeapi.UserPokemon // Every Pokemon the player owns, by UID; see collection.go
+	Party               []int                       // UIDs of the party members in slot order, the lead first
+	Inventory           map[string]int              // Item name -> count (e.g., "pokeball" -> 10)
 	Randomizer          *rand.Rand
-	CurrentAreaChoices  []pokeapi.LocationArea // For 'map' command to store choices for 'explore'
+	CurrentAreaChoices  []pokeapi.LocationArea     // For 'map' command to store choices for 'explore'
+	CurrentRegion       string                     // Region the player is in, e.g. "kanto"; saved with the game
+	CurrentLocation     string                     // Location the player is at, e.g. "pallet-town"; saved with the game
+	LocationChoices     []pokeapi.NamedAPIResource // Locations listed by 'travel <region>', for 'travel <number>'
+	LogLevel            *slog.LevelVar             // Level of the request/cache trace logger, toggled by 'debug'
+	Encounter           *Encounter                 // Wild Pokemon the player is facing, nil outside encounters
+	Clock               *GameClock                 // In-game time, for time-dependent encounters, evolutions and balls
+	Input               LineReader                 // Answers to prompts such as the evolution prompt; nil takes the default answer
+	NextUID             int                        // UID the next caught Pokemon gets; saved with the game
+	Dex                 Dex                        // Species seen and caught; saved with the game
+	Boxes               []Box                      // PC storage for the Pokemon outside the party; see storage.go
+}
+
+// newUID hands out the UID of a newly caught Pokemon, skipping any already taken.
+func (cfg *Config) newUID() int {
+	cfg.NextUID = max(cfg.NextUID, 1)
+	for {
+		uid := cfg.NextUID
+		cfg.NextUID++
+		if _, taken := cfg.Collection[uid]; !taken {
+			return uid
+		}
+	}
+}
+
+// LineReader reads a line typed by the player after showing prompt.
+type LineReader interface {
+	ReadLine(prompt string) (string, error)
 }
 
 type PokeapiClient interface {
@@ -46,6 +97,12 @@ type PokeapiClient interface {
 	FetchPokemon(pokemonName string) (pokeapi.PokemonData, error)
 	FetchPokemonSpecies(pokemonNameOrID string) (pokeapi.PokemonSpecies, error)
 	FetchEvolutionChain(url string) (pokeapi.EvolutionChainResponse, error)
+	FetchRegion(regionName string) (pokeapi.Region, error)
+	FetchLocation(locationName string) (pokeapi.Location, error)
+	All(ctx context.Context, path string, limit int, opts ...pokeapi.PageOption) iter.Seq2[pokeapi.NamedAPIResource, error]
+	FetchMove(moveName string) (pokeapi.Move, error)
+	FetchPokedex(pokedexName string) (pokeapi.Pokedex, error)
+	FetchGeneration(generationName string) (pokeapi.Generation, error)
 }
 
 type Pokecache interface {
@@ -57,4 +114,5 @@ type cliCommand struct {
 	Name        string
 	Description string
 	Callback    func(*Config, ...string) error
+	Action      bool // Actions take in-game time: running one moves a simulated clock forward
 }
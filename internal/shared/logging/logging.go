@@ -0,0 +1,23 @@
+// Package logging builds the request/cache trace logger shared by the API client,
+// the cache and the REPL's 'debug' command.
+package logging
+
+import (
+	"io"
+	"log/slog"
+)
+
+// LevelOff is above every level slog emits, so a logger at this level stays silent.
+const LevelOff = slog.Level(1 << 10)
+
+// New returns a text logger writing to w whose level can be changed at runtime
+// through level, e.g. by 'debug on' / 'debug off'.
+func New(w io.Writer, level *slog.LevelVar) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// Discard returns a logger that drops everything. It is the default for
+// components that accept an optional logger.
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
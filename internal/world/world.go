@@ -0,0 +1,235 @@
+// Package world holds the overworld maps: which PokeAPI locations of a region are
+// connected to each other, and in which direction. PokeAPI has no adjacency data, so
+// the maps are curated by hand in maps/<region>.json and embedded in the binary.
+package world
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+)
+
+//go:embed maps/*.json
+var mapFiles embed.FS
+
+// Direction is a compass direction a path leaves a location in.
+type Direction string
+
+const (
+	North Direction = "north"
+	East  Direction = "east"
+	South Direction = "south"
+	West  Direction = "west"
+)
+
+// directions lists every Direction in the order exits are shown.
+var directions = []Direction{North, East, South, West}
+
+// Opposite returns the direction that leads back, e.g. South for North.
+func (d Direction) Opposite() Direction {
+	switch d {
+	case North:
+		return South
+	case South:
+		return North
+	case East:
+		return West
+	case West:
+		return East
+	default:
+		return ""
+	}
+}
+
+// ParseDirection parses a direction name or its first letter, e.g. "north" or "n".
+func ParseDirection(s string) (Direction, bool) {
+	s = strings.ToLower(s)
+	for _, d := range directions {
+		if s == string(d) || s == string(d[0]) {
+			return d, true
+		}
+	}
+	return "", false
+}
+
+// Path is a two-way connection between two locations, as written in the map files:
+// To lies in Direction of From, so From lies in the opposite direction of To.
+type Path struct {
+	From      string    `json:"from"`
+	Direction Direction `json:"direction"`
+	To        string    `json:"to"`
+}
+
+// Exit is a path leaving a location.
+type Exit struct {
+	Direction Direction
+	To        string
+}
+
+// Map is the overworld of one region.
+type Map struct {
+	Region string
+	Start  string // Where a journey through the region begins, e.g. "pallet-town"
+
+	exits map[string]map[Direction]string
+}
+
+type mapFile struct {
+	Region string `json:"region"`
+	Start  string `json:"start"`
+	Paths  []Path `json:"paths"`
+}
+
+// maps holds the embedded map of every region that has one, by region name.
+var maps = mustLoadMaps()
+
+func mustLoadMaps() map[string]*Map {
+	files, err := mapFiles.ReadDir("maps")
+	if err != nil {
+		panic(fmt.Sprintf("world: reading embedded maps: %v", err))
+	}
+	loaded := make(map[string]*Map, len(files))
+	for _, file := range files {
+		data, err := mapFiles.ReadFile(path.Join("maps", file.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("world: reading %s: %v", file.Name(), err))
+		}
+		m, err := Parse(data)
+		if err != nil {
+			panic(fmt.Sprintf("world: %s: %v", file.Name(), err))
+		}
+		loaded[m.Region] = m
+	}
+	return loaded
+}
+
+// Parse reads a map file. Every path is added in both directions, and a location
+// can have at most one exit per direction.
+func Parse(data []byte) (*Map, error) {
+	var file mapFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid map: %w", err)
+	}
+	if file.Region == "" {
+		return nil, fmt.Errorf("map has no region")
+	}
+
+	m := &Map{Region: file.Region, Start: file.Start, exits: make(map[string]map[Direction]string)}
+	for _, p := range file.Paths {
+		if p.From == "" || p.To == "" || p.From == p.To {
+			return nil, fmt.Errorf("invalid path %s -> %s", p.From, p.To)
+		}
+		if p.Direction.Opposite() == "" {
+			return nil, fmt.Errorf("path %s -> %s has unknown direction %q", p.From, p.To, p.Direction)
+		}
+		if err := m.connect(p.From, p.Direction, p.To); err != nil {
+			return nil, err
+		}
+		if err := m.connect(p.To, p.Direction.Opposite(), p.From); err != nil {
+			return nil, err
+		}
+	}
+	if file.Start != "" && !m.Has(file.Start) {
+		return nil, fmt.Errorf("start location %s is not on the map", file.Start)
+	}
+	return m, nil
+}
+
+func (m *Map) connect(from string, d Direction, to string) error {
+	if m.exits[from] == nil {
+		m.exits[from] = make(map[Direction]string)
+	}
+	if existing, taken := m.exits[from][d]; taken && existing != to {
+		return fmt.Errorf("%s already leads %s to %s, not %s", from, d, existing, to)
+	}
+	m.exits[from][d] = to
+	return nil
+}
+
+// Lookup returns the map of a region, if it has one.
+func Lookup(region string) (*Map, bool) {
+	m, ok := maps[region]
+	return m, ok
+}
+
+// Regions returns the names of the regions that have a map, sorted.
+func Regions() []string {
+	names := make([]string, 0, len(maps))
+	for name := range maps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Has reports whether location is on the map.
+func (m *Map) Has(location string) bool {
+	_, ok := m.exits[location]
+	return ok
+}
+
+// Locations returns every location on the map, sorted.
+func (m *Map) Locations() []string {
+	names := make([]string, 0, len(m.exits))
+	for name := range m.exits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Exits returns the paths leaving location, in north, east, south, west order.
+func (m *Map) Exits(location string) []Exit {
+	var exits []Exit
+	for _, d := range directions {
+		if to, ok := m.exits[location][d]; ok {
+			exits = append(exits, Exit{Direction: d, To: to})
+		}
+	}
+	return exits
+}
+
+// Neighbor returns the location reached by leaving location in direction d.
+func (m *Map) Neighbor(location string, d Direction) (string, bool) {
+	to, ok := m.exits[location][d]
+	return to, ok
+}
+
+// Adjacent reports whether a single path connects from and to.
+func (m *Map) Adjacent(from, to string) bool {
+	return slices.ContainsFunc(m.Exits(from), func(e Exit) bool { return e.To == to })
+}
+
+// Route finds a shortest route from one location to another with a breadth-first
+// search. The route starts with from and ends with to; ok is false if either location
+// is not on the map or no route connects them.
+func (m *Map) Route(from, to string) (route []string, ok bool) {
+	if !m.Has(from) || !m.Has(to) {
+		return nil, false
+	}
+
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == to {
+			for step := to; step != ""; step = previous[step] {
+				route = append(route, step)
+			}
+			slices.Reverse(route)
+			return route, true
+		}
+		for _, exit := range m.Exits(current) {
+			if _, seen := previous[exit.To]; !seen {
+				previous[exit.To] = current
+				queue = append(queue, exit.To)
+			}
+		}
+	}
+	return nil, false
+}
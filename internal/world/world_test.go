@@ -0,0 +1,96 @@
+package world_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/voidarchive/pokedex/internal/world"
+)
+
+func TestEmbeddedMapsLoad(t *testing.T) {
+	if got := fmt.Sprint(world.Regions()); got != "[kanto sinnoh]" {
+		t.Fatalf("Regions() = %s; want [kanto sinnoh]", got)
+	}
+
+	for _, region := range world.Regions() {
+		m, _ := world.Lookup(region)
+		for _, location := range m.Locations() {
+			// Every location can be reached from the start, and every path leads back.
+			if _, ok := m.Route(m.Start, location); !ok {
+				t.Errorf("%s: %s can't be reached from %s", region, location, m.Start)
+			}
+			for _, exit := range m.Exits(location) {
+				if back, _ := m.Neighbor(exit.To, exit.Direction.Opposite()); back != location {
+					t.Errorf("%s: going %s from %s to %s, the way back leads to %q", region, exit.Direction, location, exit.To, back)
+				}
+			}
+		}
+	}
+
+	if _, ok := world.Lookup("atlantis"); ok {
+		t.Errorf("expected no map for an unknown region")
+	}
+}
+
+func TestExitsAndNeighbors(t *testing.T) {
+	kanto, _ := world.Lookup("kanto")
+
+	if got := fmt.Sprint(kanto.Exits("viridian-city")); got != "[{north kanto-route-2} {south kanto-route-1} {west kanto-route-22}]" {
+		t.Errorf("viridian-city exits = %s", got)
+	}
+	if to, ok := kanto.Neighbor("pallet-town", world.North); !ok || to != "kanto-route-1" {
+		t.Errorf("north of pallet-town = %q, %v; want kanto-route-1", to, ok)
+	}
+	if _, ok := kanto.Neighbor("pallet-town", world.West); ok {
+		t.Errorf("expected no path west of pallet-town")
+	}
+	if !kanto.Adjacent("kanto-route-1", "pallet-town") || kanto.Adjacent("pallet-town", "viridian-city") {
+		t.Errorf("pallet-town should only be adjacent to kanto-route-1")
+	}
+}
+
+func TestRoute(t *testing.T) {
+	kanto, _ := world.Lookup("kanto")
+
+	route, ok := kanto.Route("pallet-town", "pewter-city")
+	if !ok {
+		t.Fatalf("expected a route from pallet-town to pewter-city")
+	}
+	if got := strings.Join(route, " > "); got != "pallet-town > kanto-route-1 > viridian-city > kanto-route-2 > viridian-forest > pewter-city" {
+		t.Errorf("route = %s", got)
+	}
+
+	if route, ok := kanto.Route("cerulean-city", "cerulean-city"); !ok || len(route) != 1 {
+		t.Errorf("route to the same location = %v, %v; want just that location", route, ok)
+	}
+	if _, ok := kanto.Route("pallet-town", "canalave-city"); ok {
+		t.Errorf("expected no route to a location on another map")
+	}
+}
+
+func TestParseDirection(t *testing.T) {
+	for input, want := range map[string]world.Direction{"north": world.North, "S": world.South, "e": world.East, "West": world.West} {
+		if got, ok := world.ParseDirection(input); !ok || got != want {
+			t.Errorf("ParseDirection(%q) = %q, %v; want %q", input, got, ok, want)
+		}
+	}
+	if _, ok := world.ParseDirection("up"); ok {
+		t.Errorf("expected up not to be a direction")
+	}
+}
+
+func TestParseRejectsInvalidMaps(t *testing.T) {
+	tests := map[string]string{
+		"no region":            `{"paths": []}`,
+		"unknown direction":    `{"region": "r", "paths": [{"from": "a", "direction": "up", "to": "b"}]}`,
+		"conflicting exits":    `{"region": "r", "paths": [{"from": "a", "direction": "north", "to": "b"}, {"from": "a", "direction": "north", "to": "c"}]}`,
+		"conflicting way back": `{"region": "r", "paths": [{"from": "a", "direction": "north", "to": "c"}, {"from": "b", "direction": "north", "to": "c"}]}`,
+		"start not on the map": `{"region": "r", "start": "z", "paths": [{"from": "a", "direction": "north", "to": "b"}]}`,
+	}
+	for name, data := range tests {
+		if _, err := world.Parse([]byte(data)); err == nil {
+			t.Errorf("%s: expected Parse to fail", name)
+		}
+	}
+}
@@ -1,17 +1,46 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
 	"time"
 
 	"github.com/voidarchive/pokedex/internal/pokeapi"
 	"github.com/voidarchive/pokedex/internal/pokecache"
 	"github.com/voidarchive/pokedex/internal/repl"
+	"github.com/voidarchive/pokedex/internal/shared/logging"
 )
 
 func main() {
+	verbose := flag.Bool("verbose", false, "log API requests and cache activity (to stderr, or to --log-file)")
+	logFile := flag.String("log-file", "", "write the request/cache log to this file instead of stderr (implies --verbose)")
+	flag.Parse()
+
+	var logOutput io.Writer = os.Stderr
+	if *logFile != "" {
+		f, err := os.OpenFile(*logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not open log file: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		logOutput = f
+	}
+
+	// The level is shared with the REPL so 'debug on|off' can change it at runtime.
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(logging.LevelOff)
+	if *verbose || *logFile != "" {
+		logLevel.Set(slog.LevelDebug)
+	}
+	logger := logging.New(logOutput, logLevel)
+
 	cacheInterval := 5 * time.Minute
-	cache := pokecache.NewCache(cacheInterval)
+	cache := pokecache.NewCache(cacheInterval, pokecache.WithLogger(logger.With("component", "cache")))
 
-	pokeAPIClient := pokeapi.NewClient(cache)
-	repl.StartRepl(&pokeAPIClient, cache)
+	pokeAPIClient := pokeapi.NewClient(cache, pokeapi.WithLogger(logger.With("component", "pokeapi")))
+	repl.StartRepl(&pokeAPIClient, cache, logLevel)
 }